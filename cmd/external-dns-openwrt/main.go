@@ -0,0 +1,97 @@
+// Command external-dns-openwrt is an external-dns webhook provider publishing
+// records to the dnsmasq of an OpenWRT router.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/renanqts/openwrt-sdk/internal/webhook"
+	"github.com/renanqts/openwrt-sdk/pkg/sdk"
+)
+
+func main() {
+	var (
+		listen   = flag.String("listen", env("WEBHOOK_LISTEN", "localhost:8888"), "address serving the webhook")
+		addr     = flag.String("openwrt-addr", env("OPENWRT_ADDR", ""), "router address, e.g. https://192.168.1.1")
+		username = flag.String("openwrt-username", env("OPENWRT_USERNAME", "root"), "router username")
+		password = flag.String("openwrt-password", env("OPENWRT_PASSWORD", ""), "router password")
+		insecure = flag.Bool("openwrt-insecure", envBool("OPENWRT_INSECURE"), "skip TLS verification")
+		owner    = flag.String("owner", env("WEBHOOK_OWNER", "external-dns"), "owner stored on managed records")
+		include  = flag.String("domain-filter", env("WEBHOOK_DOMAIN_FILTER", ""), "comma separated domains to manage")
+		exclude  = flag.String("exclude-domains", env("WEBHOOK_EXCLUDE_DOMAINS", ""), "comma separated domains to ignore")
+	)
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	client, err := sdk.New(*addr, *username, *password, 1, *insecure)
+	if err != nil {
+		logger.Error("creating openwrt client", "error", err)
+		os.Exit(1)
+	}
+
+	provider, err := webhook.New(client, *owner, webhook.DomainFilter{
+		Include: split(*include),
+		Exclude: split(*exclude),
+	}, logger)
+	if err != nil {
+		logger.Error("creating webhook provider", "error", err)
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           provider.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("serving webhook", "addr", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("serving webhook", "error", err)
+		os.Exit(1)
+	}
+}
+
+func env(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return fallback
+}
+
+func envBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
+
+func split(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,340 @@
+// Package fakerouter provides an in-memory LuCI RPC server for end-to-end tests.
+package fakerouter
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+const (
+	authPath = "/cgi-bin/luci/rpc/auth"
+	uciPath  = "/cgi-bin/luci/rpc/uci"
+	sysPath  = "/cgi-bin/luci/rpc/sys"
+
+	// Token is the session token handed out on login
+	Token = "fakerouter"
+)
+
+type section struct {
+	name      string
+	kind      string
+	anonymous bool
+	options   map[string]any
+}
+
+// Router is an in-memory OpenWRT router speaking the LuCI RPC protocol.
+type Router struct {
+	Username string
+	Password string
+
+	mu      sync.Mutex
+	configs map[string][]*section
+	staged  map[string][]*section
+	nextID  int
+	commits map[string]int
+	failing map[string]bool
+	outputs map[string]string
+	history []string
+}
+
+// New creates an empty router accepting the given credentials.
+func New(username, password string) *Router {
+	return &Router{
+		Username: username,
+		Password: password,
+		configs:  make(map[string][]*section),
+		staged:   make(map[string][]*section),
+		commits:  make(map[string]int),
+		failing:  make(map[string]bool),
+		outputs:  make(map[string]string),
+	}
+}
+
+// Start serves the router on a local test server.
+func (r *Router) Start() *httptest.Server {
+	return httptest.NewServer(r)
+}
+
+// AddSection adds a section to config and returns its name. An empty name
+// creates an anonymous section.
+func (r *Router) AddSection(config, kind, name string, options map[string]any) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.addSection(config, kind, name, options)
+}
+
+// Sections returns the sections of config with the given type, in order,
+// as returned by get_all.
+func (r *Router) Sections(config, kind string) []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []map[string]any
+	for index, s := range r.configs[config] {
+		if kind == "" || s.kind == kind {
+			result = append(result, s.dump(index))
+		}
+	}
+
+	return result
+}
+
+// SetOutput sets the output of a command run through sys exec. Commands
+// without an output return an empty string.
+func (r *Router) SetOutput(command, output string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.outputs[command] = output
+}
+
+// History returns the commands run through sys exec, in order.
+func (r *Router) History() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.history)
+}
+
+// Commits returns how many times config was committed.
+func (r *Router) Commits(config string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.commits[config]
+}
+
+// FailCommit makes the commits of config fail, leaving its changes staged.
+func (r *Router) FailCommit(config string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failing[config] = true
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var payload struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+		Params []any  `json:"params"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		result any
+		err    error
+	)
+	switch req.URL.Path {
+	case authPath:
+		result = r.login(payload.Params)
+	case uciPath:
+		if req.URL.Query().Get("auth") != Token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		result, err = r.uci(payload.Method, payload.Params)
+	case sysPath:
+		if req.URL.Query().Get("auth") != Token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		result, err = r.sys(payload.Method, payload.Params)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	response := map[string]any{"id": payload.ID, "result": result, "error": nil}
+	if err != nil {
+		response["result"] = nil
+		response["error"] = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (r *Router) login(params []any) any {
+	if len(params) == 2 && params[0] == r.Username && params[1] == r.Password {
+		return Token
+	}
+
+	return nil
+}
+
+func (r *Router) uci(method string, params []any) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	args := make([]string, len(params))
+	for index, param := range params {
+		if value, ok := param.(string); ok {
+			args[index] = value
+		}
+	}
+
+	if slices.Contains([]string{"add", "set", "delete", "reorder"}, method) && len(args) > 0 {
+		r.stage(args[0])
+	}
+
+	switch {
+	case method == "get_all" && len(args) == 1:
+		result := make(map[string]any)
+		for index, s := range r.configs[args[0]] {
+			result[s.name] = s.dump(index)
+		}
+		return result, nil
+	case method == "get_all" && len(args) == 2:
+		index, s := r.find(args[0], args[1])
+		if s == nil {
+			return nil, nil
+		}
+		return s.dump(index), nil
+	case method == "get" && len(args) == 3:
+		_, s := r.find(args[0], args[1])
+		if s == nil {
+			return nil, nil
+		}
+		return s.options[args[2]], nil
+	case method == "add" && len(args) == 2:
+		return r.addSection(args[0], args[1], "", nil), nil
+	case method == "set" && len(args) == 3:
+		if _, s := r.find(args[0], args[1]); s != nil {
+			s.kind = args[2]
+			return true, nil
+		}
+		r.addSection(args[0], args[2], args[1], nil)
+		return true, nil
+	case method == "set" && len(params) == 4:
+		_, s := r.find(args[0], args[1])
+		if s == nil {
+			return false, nil
+		}
+		s.options[args[2]] = params[3]
+		return true, nil
+	case method == "delete" && len(args) == 2:
+		index, s := r.find(args[0], args[1])
+		if s == nil {
+			return false, nil
+		}
+		r.configs[args[0]] = slices.Delete(r.configs[args[0]], index, index+1)
+		return true, nil
+	case method == "delete" && len(args) == 3:
+		_, s := r.find(args[0], args[1])
+		if s == nil {
+			return false, nil
+		}
+		delete(s.options, args[2])
+		return true, nil
+	case method == "reorder" && len(args) == 3:
+		index, s := r.find(args[0], args[1])
+		position, err := strconv.Atoi(args[2])
+		if s == nil || err != nil {
+			return false, nil
+		}
+		sections := slices.Delete(r.configs[args[0]], index, index+1)
+		position = min(max(position, 0), len(sections))
+		r.configs[args[0]] = slices.Insert(sections, position, s)
+		return true, nil
+	case method == "commit" && len(args) == 1:
+		if r.failing[args[0]] {
+			return nil, fmt.Errorf("commit of %s failed", args[0])
+		}
+		delete(r.staged, args[0])
+		r.commits[args[0]]++
+		return true, nil
+	case method == "revert" && len(args) == 1:
+		if sections, ok := r.staged[args[0]]; ok {
+			r.configs[args[0]] = sections
+			delete(r.staged, args[0])
+		}
+		return true, nil
+	default:
+		return nil, fmt.Errorf("unsupported uci call: %s %v", method, params)
+	}
+}
+
+func (r *Router) sys(method string, params []any) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if method != "exec" || len(params) != 1 {
+		return nil, fmt.Errorf("unsupported sys call: %s %v", method, params)
+	}
+
+	command, _ := params[0].(string)
+	r.history = append(r.history, command)
+	return r.outputs[command], nil
+}
+
+func (r *Router) find(config, name string) (int, *section) {
+	for index, s := range r.configs[config] {
+		if s.name == name {
+			return index, s
+		}
+	}
+
+	return -1, nil
+}
+
+func (r *Router) addSection(config, kind, name string, options map[string]any) string {
+	anonymous := name == ""
+	if anonymous {
+		r.nextID++
+		name = "cfg" + strconv.Itoa(r.nextID)
+	}
+
+	s := &section{
+		name:      name,
+		kind:      kind,
+		anonymous: anonymous,
+		options:   make(map[string]any),
+	}
+	for key, value := range options {
+		s.options[key] = value
+	}
+	r.configs[config] = append(r.configs[config], s)
+
+	return name
+}
+
+// stage keeps the committed sections of config before its first change, so
+// a revert can restore them.
+func (r *Router) stage(config string) {
+	if _, ok := r.staged[config]; ok {
+		return
+	}
+
+	sections := make([]*section, 0, len(r.configs[config]))
+	for _, s := range r.configs[config] {
+		clone := *s
+		clone.options = maps.Clone(s.options)
+		sections = append(sections, &clone)
+	}
+	r.staged[config] = sections
+}
+
+func (s *section) dump(index int) map[string]any {
+	result := map[string]any{
+		".name":      s.name,
+		".type":      s.kind,
+		".anonymous": s.anonymous,
+		".index":     index,
+	}
+	for key, value := range s.options {
+		result[key] = value
+	}
+
+	return result
+}
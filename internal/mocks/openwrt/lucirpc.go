@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/renanqts/openwrt-sdk/pkg/openwrt (interfaces: LuciRPC)
+// Source: github.com/renanqts/openwrt-sdk/pkg/sdk (interfaces: LuciRPC)
 //
 // Generated by this command:
 //
@@ -40,6 +40,21 @@ func (m *MockLuciRPC) EXPECT() *MockLuciRPCMockRecorder {
 	return m.recorder
 }
 
+// Sys mocks base method.
+func (m *MockLuciRPC) Sys(arg0 context.Context, arg1 string, arg2 []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sys", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sys indicates an expected call of Sys.
+func (mr *MockLuciRPCMockRecorder) Sys(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sys", reflect.TypeOf((*MockLuciRPC)(nil).Sys), arg0, arg1, arg2)
+}
+
 // Uci mocks base method.
 func (m *MockLuciRPC) Uci(arg0 context.Context, arg1 string, arg2 []string) (string, error) {
 	m.ctrl.T.Helper()
@@ -54,3 +69,18 @@ func (mr *MockLuciRPCMockRecorder) Uci(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uci", reflect.TypeOf((*MockLuciRPC)(nil).Uci), arg0, arg1, arg2)
 }
+
+// UciSetList mocks base method.
+func (m *MockLuciRPC) UciSetList(arg0 context.Context, arg1, arg2, arg3 string, arg4 []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UciSetList", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UciSetList indicates an expected call of UciSetList.
+func (mr *MockLuciRPCMockRecorder) UciSetList(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UciSetList", reflect.TypeOf((*MockLuciRPC)(nil).UciSetList), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,108 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestQRCode(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "QR Code Suite")
+	defer GinkgoRecover()
+}
+
+var _ = Describe("QR code", func() {
+	It("encode a version 1 code", func() {
+		code, err := Encode([]byte("hello, openwrt"), Medium)
+		Expect(err).To(BeNil())
+		Expect(render(code)).To(Equal([]string{
+			"#######...##..#######",
+			"#.....#.#####.#.....#",
+			"#.###.#.....#.#.###.#",
+			"#.###.#..#.#..#.###.#",
+			"#.###.#.#.#.#.#.###.#",
+			"#.....#..###..#.....#",
+			"#######.#.#.#.#######",
+			".........###.........",
+			"#.#.#.#..####...#..#.",
+			"##.#........#.###..##",
+			"#.#..##..#.###.######",
+			"..#..#..#..##..#...#.",
+			"..##.####...##..#....",
+			"........#.#.##.##.###",
+			"#######....##.#.#.###",
+			"#.....#..#..#..#...##",
+			"#.###.#.#.###..#...#.",
+			"#.###.#..##.#..##.##.",
+			"#.###.#.##.####.#.#.#",
+			"#.....#...###...#..#.",
+			"#######.##.#...#...##",
+		}))
+	})
+
+	It("pick the smallest version", func() {
+		sizes := map[int]int{17: 21, 18: 25, 271: 57, 272: 61, 2953: 177}
+		for length, size := range sizes {
+			code, err := Encode([]byte(strings.Repeat("x", length)), Low)
+			Expect(err).To(BeNil())
+			Expect(code.Size()).To(Equal(size), "%d bytes", length)
+		}
+
+		_, err := Encode([]byte(strings.Repeat("x", 2954)), Low)
+		Expect(err).To(MatchError(ErrTooLong))
+		_, err = Encode([]byte(strings.Repeat("x", 1274)), High)
+		Expect(err).To(MatchError(ErrTooLong))
+	})
+
+	It("draw the version information", func() {
+		code, err := Encode([]byte(strings.Repeat("x", 120)), Medium)
+		Expect(err).To(BeNil())
+		Expect(code.Size()).To(Equal(45))
+
+		// version 7 is 000111 110010 010100, from the least significant bit
+		var bits int
+		for i := range 18 {
+			if code.Dark(code.Size()-11+i%3, i/3) {
+				bits |= 1 << i
+			}
+			Expect(code.Dark(i/3, code.Size()-11+i%3)).To(Equal(code.Dark(code.Size()-11+i%3, i/3)))
+		}
+		Expect(bits).To(Equal(0x07C94))
+	})
+
+	It("render for terminals", func() {
+		code, err := Encode([]byte("wireguard"), Low)
+		Expect(err).To(BeNil())
+
+		lines := strings.Split(strings.TrimSuffix(code.Terminal(), "\n"), "\n")
+		Expect(lines).To(HaveLen(13))
+		for _, line := range lines {
+			Expect(utf8.RuneCountInString(line)).To(Equal(25))
+		}
+		Expect(lines[0]).To(Equal(strings.Repeat("█", 25)))
+		// the top of the finder pattern and the light ring below it
+		Expect(lines[1]).To(HavePrefix("██ ▄▄▄▄▄ █"))
+		Expect(lines[12]).To(Equal(strings.Repeat("█", 25)))
+	})
+})
+
+func render(code *Code) []string {
+	var lines []string
+	for y := range code.Size() {
+		var line strings.Builder
+		for x := range code.Size() {
+			if code.Dark(x, y) {
+				line.WriteByte('#')
+			} else {
+				line.WriteByte('.')
+			}
+		}
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
@@ -0,0 +1,283 @@
+// Package webhook implements the external-dns webhook provider protocol on
+// top of the OpenWRT SDK DNS records.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/renanqts/openwrt-sdk/pkg/sdk"
+)
+
+// MediaType is the content type negotiated with external-dns
+const MediaType = "application/external.dns.webhook+json;version=1"
+
+var supportedTypes = []string{"A", "CNAME"}
+
+// DNS is the part of the SDK used by the provider.
+type DNS interface {
+	GetDNSRecords(context.Context) (map[string]sdk.DNSRecord, error)
+	ChangeDNSRecords(context.Context, []sdk.DNSRecord, []sdk.DNSRecord) error
+}
+
+// Provider serves the DNS records of an OpenWRT device to external-dns.
+// Ownership is kept in the owner option of each record, so external-dns can
+// run with the noop registry and never touches records it did not create.
+type Provider struct {
+	dns    DNS
+	owner  string
+	filter DomainFilter
+	logger *slog.Logger
+}
+
+// New creates a new webhook provider managing the records of owner.
+func New(dns DNS, owner string, filter DomainFilter, logger *slog.Logger) (*Provider, error) {
+	if dns == nil {
+		return nil, errors.New("dns is nil")
+	}
+
+	if owner == "" {
+		return nil, errors.New("owner is empty")
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Provider{
+		dns:    dns,
+		owner:  owner,
+		filter: filter,
+		logger: logger,
+	}, nil
+}
+
+// Handler returns the HTTP handler implementing the webhook protocol.
+func (p *Provider) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", p.negotiate)
+	mux.HandleFunc("GET /records", p.getRecords)
+	mux.HandleFunc("POST /records", p.postRecords)
+	mux.HandleFunc("POST /adjustendpoints", p.adjustEndpoints)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+// Records returns the endpoints owned by the provider.
+func (p *Provider) Records(ctx context.Context) ([]*Endpoint, error) {
+	records, err := p.ownedRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*Endpoint, 0)
+	for _, record := range records {
+		name, target := recordName(record)
+		index := slices.IndexFunc(endpoints, func(endpoint *Endpoint) bool {
+			return endpoint.RecordType == record.Type && endpoint.DNSName == name
+		})
+		if index < 0 {
+			endpoints = append(endpoints, &Endpoint{DNSName: name, RecordType: record.Type})
+			index = len(endpoints) - 1
+		}
+		endpoints[index].Targets = append(endpoints[index].Targets, target)
+	}
+
+	for _, endpoint := range endpoints {
+		slices.Sort(endpoint.Targets)
+	}
+	slices.SortFunc(endpoints, func(a, b *Endpoint) int {
+		if c := strings.Compare(a.DNSName, b.DNSName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RecordType, b.RecordType)
+	})
+
+	return endpoints, nil
+}
+
+// ApplyChanges writes the changes to the OpenWRT device in a single commit.
+// Targets present on both sides of an update are left untouched and deleting
+// a record that is already gone is not an error.
+func (p *Provider) ApplyChanges(ctx context.Context, changes Changes) error {
+	deletes := p.toRecords(slices.Concat(changes.Delete, changes.UpdateOld))
+	creates := p.toRecords(slices.Concat(changes.Create, changes.UpdateNew))
+
+	var unchanged []sdk.DNSRecord
+	for _, record := range creates {
+		if slices.Contains(deletes, record) {
+			unchanged = append(unchanged, record)
+		}
+	}
+	deletes = slices.DeleteFunc(deletes, func(record sdk.DNSRecord) bool {
+		return slices.Contains(unchanged, record)
+	})
+	creates = slices.DeleteFunc(creates, func(record sdk.DNSRecord) bool {
+		return slices.Contains(unchanged, record)
+	})
+
+	if len(deletes) > 0 {
+		current, err := p.ownedRecords(ctx)
+		if err != nil {
+			return err
+		}
+
+		deletes = slices.DeleteFunc(deletes, func(record sdk.DNSRecord) bool {
+			return !slices.ContainsFunc(current, record.Identifies)
+		})
+	}
+
+	if len(deletes) == 0 && len(creates) == 0 {
+		return nil
+	}
+
+	p.logger.Info("applying records", "deletes", deletes, "creates", creates)
+
+	return p.dns.ChangeDNSRecords(ctx, deletes, creates)
+}
+
+// AdjustEndpoints drops the endpoints the provider cannot serve.
+func (p *Provider) AdjustEndpoints(endpoints []*Endpoint) []*Endpoint {
+	adjusted := make([]*Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !slices.Contains(supportedTypes, endpoint.RecordType) || !p.filter.Match(endpoint.DNSName) {
+			p.logger.Debug("skipping endpoint", "name", endpoint.DNSName, "type", endpoint.RecordType)
+			continue
+		}
+		adjusted = append(adjusted, endpoint)
+	}
+
+	return adjusted
+}
+
+// Match reports whether the name is served according to the filter.
+func (f DomainFilter) Match(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	matches := func(domains []string) bool {
+		return slices.ContainsFunc(domains, func(domain string) bool {
+			domain = strings.ToLower(strings.Trim(domain, "."))
+			return name == domain || strings.HasSuffix(name, "."+domain)
+		})
+	}
+
+	if matches(f.Exclude) {
+		return false
+	}
+
+	return len(f.Include) == 0 || matches(f.Include)
+}
+
+func (p *Provider) negotiate(w http.ResponseWriter, r *http.Request) {
+	p.write(w, http.StatusOK, p.filter)
+}
+
+func (p *Provider) getRecords(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := p.Records(r.Context())
+	if err != nil {
+		p.error(w, err)
+		return
+	}
+
+	p.write(w, http.StatusOK, endpoints)
+}
+
+func (p *Provider) postRecords(w http.ResponseWriter, r *http.Request) {
+	var changes Changes
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := p.ApplyChanges(r.Context(), changes); err != nil {
+		p.error(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Provider) adjustEndpoints(w http.ResponseWriter, r *http.Request) {
+	var endpoints []*Endpoint
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.write(w, http.StatusOK, p.AdjustEndpoints(endpoints))
+}
+
+func (p *Provider) write(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		p.logger.Error("writing response", "error", err)
+	}
+}
+
+func (p *Provider) error(w http.ResponseWriter, err error) {
+	p.logger.Error("handling request", "error", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// ownedRecords returns the supported records of the owner inside the filter.
+func (p *Provider) ownedRecords(ctx context.Context) ([]sdk.DNSRecord, error) {
+	records, err := p.dns.GetDNSRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []sdk.DNSRecord
+	for _, record := range records {
+		name, _ := recordName(record)
+		if record.Owner == p.owner && slices.Contains(supportedTypes, record.Type) && p.filter.Match(name) {
+			owned = append(owned, record)
+		}
+	}
+
+	return owned, nil
+}
+
+// toRecords converts the endpoints to one owned record per target.
+func (p *Provider) toRecords(endpoints []*Endpoint) []sdk.DNSRecord {
+	var records []sdk.DNSRecord
+	for _, endpoint := range endpoints {
+		if endpoint == nil || !p.filter.Match(endpoint.DNSName) {
+			continue
+		}
+
+		for _, target := range endpoint.Targets {
+			var record sdk.DNSRecord
+			switch endpoint.RecordType {
+			case "A":
+				record = sdk.DNSRecord{Type: "A", Name: endpoint.DNSName, IP: target, Owner: p.owner}
+			case "CNAME":
+				record = sdk.DNSRecord{Type: "CNAME", CName: endpoint.DNSName, Target: target, Owner: p.owner}
+			default:
+				p.logger.Warn("unsupported record type", "name", endpoint.DNSName, "type", endpoint.RecordType)
+				continue
+			}
+
+			if !slices.Contains(records, record) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	return records
+}
+
+func recordName(record sdk.DNSRecord) (string, string) {
+	if record.Type == "CNAME" {
+		return record.CName, record.Target
+	}
+
+	return record.Name, record.IP
+}
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+	"github.com/renanqts/openwrt-sdk/pkg/sdk"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Suite")
+	defer GinkgoRecover()
+}
+
+var _ = Describe("Webhook", func() {
+	var (
+		router  *fakerouter.Router
+		routerS *httptest.Server
+		server  *httptest.Server
+	)
+
+	BeforeEach(func() {
+		router = fakerouter.New("root", "password")
+		router.AddSection("dhcp", "dnsmasq", "", map[string]any{"domain": "lan"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "nas.home.lan", "ip": "192.168.1.10"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "web.home.lan", "ip": "192.168.1.20", "owner": "k8s"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "web.home.lan", "ip": "192.168.1.21", "owner": "k8s"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "web.example.com", "ip": "192.168.1.30", "owner": "k8s"})
+		routerS = router.Start()
+
+		client, err := sdk.New(routerS.URL, "root", "password", 1, false)
+		Expect(err).To(BeNil())
+
+		provider, err := New(client, "k8s", DomainFilter{Include: []string{"home.lan"}}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		Expect(err).To(BeNil())
+		server = httptest.NewServer(provider.Handler())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		routerS.Close()
+	})
+
+	request := func(method, path string, body any) *http.Response {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			Expect(err).To(BeNil())
+			reader = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequest(method, server.URL+path, reader)
+		Expect(err).To(BeNil())
+		req.Header.Set("Accept", MediaType)
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).To(BeNil())
+		return resp
+	}
+
+	records := func() []*Endpoint {
+		resp := request(http.MethodGet, "/records", nil)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var endpoints []*Endpoint
+		Expect(json.NewDecoder(resp.Body).Decode(&endpoints)).To(Succeed())
+		return endpoints
+	}
+
+	It("negotiates the domain filter", func() {
+		resp := request(http.MethodGet, "/", nil)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal(MediaType))
+
+		var filter DomainFilter
+		Expect(json.NewDecoder(resp.Body).Decode(&filter)).To(Succeed())
+		Expect(filter).To(Equal(DomainFilter{Include: []string{"home.lan"}}))
+	})
+
+	It("lists owned records only", func() {
+		Expect(records()).To(Equal([]*Endpoint{
+			{DNSName: "web.home.lan", RecordType: "A", Targets: []string{"192.168.1.20", "192.168.1.21"}},
+		}))
+	})
+
+	It("applies changes", func() {
+		resp := request(http.MethodPost, "/records", Changes{
+			Create: []*Endpoint{
+				{DNSName: "app.home.lan", RecordType: "CNAME", Targets: []string{"web.home.lan"}},
+				{DNSName: "app.home.lan", RecordType: "TXT", Targets: []string{"heritage=external-dns"}},
+			},
+			UpdateOld: []*Endpoint{
+				{DNSName: "web.home.lan", RecordType: "A", Targets: []string{"192.168.1.20", "192.168.1.21"}},
+			},
+			UpdateNew: []*Endpoint{
+				{DNSName: "web.home.lan", RecordType: "A", Targets: []string{"192.168.1.21", "192.168.1.22"}},
+			},
+			Delete: []*Endpoint{
+				{DNSName: "nas.home.lan", RecordType: "A", Targets: []string{"192.168.1.10"}},
+			},
+		})
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		Expect(records()).To(Equal([]*Endpoint{
+			{DNSName: "app.home.lan", RecordType: "CNAME", Targets: []string{"web.home.lan"}},
+			{DNSName: "web.home.lan", RecordType: "A", Targets: []string{"192.168.1.21", "192.168.1.22"}},
+		}))
+		// records of other owners stay untouched
+		Expect(router.Sections("dhcp", "domain")).To(ContainElement(HaveKeyWithValue("name", "nas.home.lan")))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+	})
+
+	It("deletes records scoped to a dnsmasq instance", func() {
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "db.home.lan", "ip": "192.168.1.40", "owner": "k8s", "instance": "guest"})
+		resp := request(http.MethodPost, "/records", Changes{
+			Delete: []*Endpoint{
+				{DNSName: "db.home.lan", RecordType: "A", Targets: []string{"192.168.1.40"}},
+			},
+		})
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		Expect(router.Sections("dhcp", "domain")).ToNot(ContainElement(HaveKeyWithValue("name", "db.home.lan")))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+	})
+
+	It("adjusts endpoints", func() {
+		resp := request(http.MethodPost, "/adjustendpoints", []*Endpoint{
+			{DNSName: "app.home.lan", RecordType: "A", Targets: []string{"192.168.1.40"}},
+			{DNSName: "app.home.lan", RecordType: "TXT", Targets: []string{"foobar"}},
+			{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.168.1.40"}},
+		})
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var endpoints []*Endpoint
+		Expect(json.NewDecoder(resp.Body).Decode(&endpoints)).To(Succeed())
+		Expect(endpoints).To(Equal([]*Endpoint{
+			{DNSName: "app.home.lan", RecordType: "A", Targets: []string{"192.168.1.40"}},
+		}))
+	})
+})
@@ -17,6 +17,7 @@ const (
 	rpcPath     = "/cgi-bin/luci/rpc/"
 	authPath    = rpcPath + "auth"
 	uciPath     = rpcPath + "uci"
+	sysPath     = rpcPath + "sys"
 	methodLogin = "login"
 
 	defaultTimeout = 15
@@ -36,6 +37,14 @@ type Payload struct {
 	Params []string `json:"params"`
 }
 
+// listPayload represents a JSON-RPC request payload whose params hold a list,
+// such as the values of a UCI list option.
+type listPayload struct {
+	ID     int    `json:"id"`
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+}
+
 // Response represents a JSON-RPC response.
 type Response struct {
 	ID     int `json:"id"`
@@ -86,11 +95,29 @@ func New(addr, username, password string, rpcID int, insecureSkipVerify bool) (*
 
 // Uci performs a UCI RPC call with authentication.
 func (c *LuciRPC) Uci(ctx context.Context, method string, params []string) (string, error) {
-	return c.rpcWithAuth(ctx, uciPath, method, params)
+	return c.rpcWithAuth(ctx, uciPath, method, Payload{ID: c.rpcID, Method: method, Params: params})
+}
+
+// UciSetList sets a UCI list option with authentication.
+func (c *LuciRPC) UciSetList(ctx context.Context, config, section, option string, values []string) (string, error) {
+	return c.rpcWithAuth(ctx, uciPath, "set", listPayload{
+		ID:     c.rpcID,
+		Method: "set",
+		Params: []any{config, section, option, values},
+	})
+}
+
+// Sys performs a system library RPC call, such as exec, with authentication.
+func (c *LuciRPC) Sys(ctx context.Context, method string, params []string) (string, error) {
+	return c.rpcWithAuth(ctx, sysPath, method, Payload{ID: c.rpcID, Method: method, Params: params})
 }
 
 func (c *LuciRPC) auth(ctx context.Context) error {
-	token, err := c.rpc(ctx, authPath, methodLogin, []string{c.username, c.password})
+	token, err := c.rpc(ctx, authPath, methodLogin, Payload{
+		ID:     c.rpcID,
+		Method: methodLogin,
+		Params: []string{c.username, c.password},
+	})
 	if err != nil {
 		return err
 	}
@@ -105,12 +132,8 @@ func (c *LuciRPC) auth(ctx context.Context) error {
 	return nil
 }
 
-func (c *LuciRPC) rpc(ctx context.Context, path, method string, params []string) (string, error) {
-	data, err := json.Marshal(Payload{
-		ID:     c.rpcID,
-		Method: method,
-		Params: params,
-	})
+func (c *LuciRPC) rpc(ctx context.Context, path, method string, payload any) (string, error) {
+	data, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
 	}
@@ -183,8 +206,8 @@ func (c *LuciRPC) httpError(code int) error {
 	return fmt.Errorf("http status code: %d", code)
 }
 
-func (c *LuciRPC) rpcWithAuth(ctx context.Context, path, method string, params []string) (string, error) {
-	result, err := c.rpc(ctx, path, method, params)
+func (c *LuciRPC) rpcWithAuth(ctx context.Context, path, method string, payload any) (string, error) {
+	result, err := c.rpc(ctx, path, method, payload)
 	if err == nil {
 		return result, nil
 	}
@@ -197,7 +220,7 @@ func (c *LuciRPC) rpcWithAuth(ctx context.Context, path, method string, params [
 		return "", err
 	}
 
-	return c.rpc(ctx, path, method, params)
+	return c.rpc(ctx, path, method, payload)
 }
 
 func parseString(obj any) (string, error) {
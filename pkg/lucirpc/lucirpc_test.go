@@ -3,6 +3,7 @@ package lucirpc
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -148,5 +149,29 @@ var _ = Describe("Luci RPC", func() {
 			Expect(authCalled).To(BeTrue())
 			Expect(client.token).To(Equal(expectedToken))
 		})
+
+		It("should set a list", func() {
+			mux := http.NewServeMux()
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+			u, err := url.Parse(ts.URL)
+			Expect(err).To(BeNil())
+			client, err := New("http://"+u.Host, "admin", "password", 1, true)
+			Expect(err).To(BeNil())
+
+			mux.HandleFunc(uciPath, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				Expect(err).To(BeNil())
+				Expect(string(body)).To(MatchJSON(`{"id":1,"method":"set","params":["dhcp","cfg01","rebind_domain",["lan","home"]]}`))
+
+				w.WriteHeader(http.StatusOK)
+				_, err = w.Write([]byte(`{"result":true}`))
+				Expect(err).To(BeNil())
+			})
+
+			resp, err := client.UciSetList(ctx, "dhcp", "cfg01", "rebind_domain", []string{"lan", "home"})
+			Expect(err).To(BeNil())
+			Expect(resp).To(Equal("true"))
+		})
 	})
 })
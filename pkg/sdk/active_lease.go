@@ -0,0 +1,192 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	luciLeasesCommand = "ubus call luci-rpc getDHCPLeases"
+	odhcpdCommand     = "ubus call dhcp ipv6leases"
+	dnsmasqLeasesFile = "/tmp/dhcp.leases"
+)
+
+// GetDHCPLeases retrieves the active DHCP and DHCPv6 leases from the OpenWRT
+// device. The leases reported by LuCI are used when available, otherwise the
+// dnsmasq lease file and the odhcpd leases are read directly.
+func (o *OpenWRT) GetDHCPLeases(ctx context.Context) (DHCPLeases, error) {
+	now := time.Now()
+
+	output, err := o.exec(ctx, luciLeasesCommand)
+	if err != nil {
+		return DHCPLeases{}, err
+	}
+
+	if leases, ok := parseLuciLeases(output, now); ok {
+		return leases, nil
+	}
+
+	output, err = o.exec(ctx, "cat "+dnsmasqLeasesFile)
+	if err != nil {
+		return DHCPLeases{}, err
+	}
+	leases := parseDnsmasqLeases(output)
+
+	output, err = o.exec(ctx, odhcpdCommand)
+	if err != nil {
+		return DHCPLeases{}, err
+	}
+	leases.IPv6 = append(leases.IPv6, parseOdhcpdLeases(output, now)...)
+
+	return leases, nil
+}
+
+// parseLuciLeases parses the output of the luci-rpc getDHCPLeases ubus call,
+// reporting false when the call is not available.
+func parseLuciLeases(output string, now time.Time) (DHCPLeases, bool) {
+	var result struct {
+		DHCPLeases []struct {
+			Expires  json.RawMessage `json:"expires"`
+			Hostname string          `json:"hostname"`
+			MAC      string          `json:"macaddr"`
+			IP       string          `json:"ipaddr"`
+		} `json:"dhcp_leases"`
+		DHCP6Leases []struct {
+			Expires  json.RawMessage `json:"expires"`
+			Hostname string          `json:"hostname"`
+			MAC      string          `json:"macaddr"`
+			DUID     string          `json:"duid"`
+			IP       string          `json:"ip6addr"`
+			IPs      []string        `json:"ip6addrs"`
+		} `json:"dhcp6_leases"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil || (result.DHCPLeases == nil && result.DHCP6Leases == nil) {
+		return DHCPLeases{}, false
+	}
+
+	var leases DHCPLeases
+	for _, lease := range result.DHCPLeases {
+		leases.IPv4 = append(leases.IPv4, DHCPLease{
+			MAC:      lease.MAC,
+			IP:       lease.IP,
+			Hostname: lease.Hostname,
+			Expiry:   remaining(lease.Expires, now),
+		})
+	}
+
+	for _, lease := range result.DHCP6Leases {
+		ips := lease.IPs
+		if len(ips) == 0 && lease.IP != "" {
+			ips = []string{lease.IP}
+		}
+
+		for _, ip := range ips {
+			leases.IPv6 = append(leases.IPv6, DHCPLease{
+				MAC:      lease.MAC,
+				IP:       strings.Split(ip, "/")[0],
+				Hostname: lease.Hostname,
+				ClientID: lease.DUID,
+				Expiry:   remaining(lease.Expires, now),
+			})
+		}
+	}
+
+	return leases, true
+}
+
+// parseDnsmasqLeases parses the dnsmasq lease file, whose lines hold the
+// expiry time, the MAC address (or IAID for DHCPv6), the IP address, the host
+// name and the client identifier.
+func parseDnsmasqLeases(output string) DHCPLeases {
+	var leases DHCPLeases
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 {
+			continue
+		}
+
+		expiry, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		lease := DHCPLease{
+			IP:       fields[2],
+			Hostname: unknownField(fields[3]),
+			ClientID: unknownField(fields[4]),
+		}
+		if expiry > 0 {
+			lease.Expiry = time.Unix(expiry, 0)
+		}
+
+		if strings.Contains(lease.IP, ":") {
+			leases.IPv6 = append(leases.IPv6, lease)
+			continue
+		}
+
+		lease.MAC = fields[1]
+		leases.IPv4 = append(leases.IPv4, lease)
+	}
+
+	return leases
+}
+
+// parseOdhcpdLeases parses the output of the dhcp ipv6leases ubus call.
+func parseOdhcpdLeases(output string, now time.Time) []DHCPLease {
+	var result struct {
+		Device map[string]struct {
+			Leases []struct {
+				DUID     string `json:"duid"`
+				Hostname string `json:"hostname"`
+				Valid    int64  `json:"valid"`
+				Addrs    []struct {
+					Address string `json:"address"`
+				} `json:"ipv6-addr"`
+			} `json:"leases"`
+		} `json:"device"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil
+	}
+
+	var leases []DHCPLease
+	for _, device := range result.Device {
+		for _, lease := range device.Leases {
+			for _, addr := range lease.Addrs {
+				leases = append(leases, DHCPLease{
+					IP:       addr.Address,
+					Hostname: lease.Hostname,
+					ClientID: lease.DUID,
+					Expiry:   remaining(json.RawMessage(strconv.FormatInt(lease.Valid, 10)), now),
+				})
+			}
+		}
+	}
+
+	return leases
+}
+
+// remaining converts a number of seconds left into an expiry time, anything
+// else meaning the lease never expires.
+func remaining(seconds json.RawMessage, now time.Time) time.Time {
+	value, err := strconv.ParseInt(string(seconds), 10, 64)
+	if err != nil || value < 0 {
+		return time.Time{}
+	}
+
+	return now.Add(time.Duration(value) * time.Second).Truncate(time.Second)
+}
+
+func unknownField(value string) string {
+	if value == "*" {
+		return ""
+	}
+
+	return value
+}
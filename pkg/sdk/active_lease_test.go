@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	mocks "github.com/renanqts/openwrt-sdk/internal/mocks/openwrt"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Active leases", func() {
+	var (
+		ctx         context.Context
+		mockLuciRPC *mocks.MockLuciRPC
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockLuciRPC = mocks.NewMockLuciRPC(gomock.NewController(GinkgoT()))
+	})
+
+	It("read leases from luci", func() {
+		mockLuciRPC.EXPECT().Sys(ctx, "exec", []string{luciLeasesCommand}).Return(`{
+			"dhcp_leases": [{"expires": 3600, "hostname": "tv", "macaddr": "aa:bb:cc:dd:ee:01", "ipaddr": "192.168.1.20"}],
+			"dhcp6_leases": [{"expires": false, "hostname": "tv", "duid": "0001", "ip6addrs": ["fd00::20/128", "fd00::21/128"]}]
+		}`, nil)
+
+		o := OpenWRT{
+			lucirpc: mockLuciRPC,
+		}
+		leases, err := o.GetDHCPLeases(ctx)
+		Expect(err).To(BeNil())
+		Expect(leases.IPv4).To(HaveLen(1))
+		Expect(leases.IPv4[0].IP).To(Equal("192.168.1.20"))
+		Expect(leases.IPv4[0].MAC).To(Equal("aa:bb:cc:dd:ee:01"))
+		Expect(leases.IPv4[0].Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+		Expect(leases.IPv6).To(Equal([]DHCPLease{
+			{IP: "fd00::20", Hostname: "tv", ClientID: "0001"},
+			{IP: "fd00::21", Hostname: "tv", ClientID: "0001"},
+		}))
+	})
+
+	It("fall back to dnsmasq and odhcpd", func() {
+		mockLuciRPC.EXPECT().Sys(ctx, "exec", []string{luciLeasesCommand}).Return("", nil)
+		mockLuciRPC.EXPECT().Sys(ctx, "exec", []string{"cat " + dnsmasqLeasesFile}).Return(
+			"1700000000 aa:bb:cc:dd:ee:01 192.168.1.20 tv 01:aa:bb:cc:dd:ee:01\n"+
+				"0 aa:bb:cc:dd:ee:02 192.168.1.21 * *\n", nil)
+		mockLuciRPC.EXPECT().Sys(ctx, "exec", []string{odhcpdCommand}).Return(`{"device": {"br-lan": {"leases": [
+			{"duid": "0001", "hostname": "tv", "valid": -1, "ipv6-addr": [{"address": "fd00::20"}]}
+		]}}}`, nil)
+
+		o := OpenWRT{
+			lucirpc: mockLuciRPC,
+		}
+		leases, err := o.GetDHCPLeases(ctx)
+		Expect(err).To(BeNil())
+		Expect(leases).To(Equal(DHCPLeases{
+			IPv4: []DHCPLease{
+				{MAC: "aa:bb:cc:dd:ee:01", IP: "192.168.1.20", Hostname: "tv", ClientID: "01:aa:bb:cc:dd:ee:01", Expiry: time.Unix(1700000000, 0)},
+				{MAC: "aa:bb:cc:dd:ee:02", IP: "192.168.1.21"},
+			},
+			IPv6: []DHCPLease{
+				{IP: "fd00::20", Hostname: "tv", ClientID: "0001"},
+			},
+		}))
+	})
+})
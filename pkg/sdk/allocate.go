@@ -0,0 +1,224 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ErrNoFreeIP is returned when every address of a subnet is in use
+var ErrNoFreeIP = errors.New("no free ip")
+
+// NextFreeIP returns the lowest address of the subnet not used by the router,
+// a static lease, an A record, an active lease or the dynamic DHCP range, and
+// not excluded by the allocation.
+func (o *OpenWRT) NextFreeIP(ctx context.Context, allocation IPAllocation) (string, error) {
+	prefix, used, err := o.usedIPs(ctx, allocation)
+	if err != nil {
+		return "", err
+	}
+
+	ranges, err := parseIPRanges(allocation.Exclude)
+	if err != nil {
+		return "", err
+	}
+
+	last := lastIP(prefix)
+	for addr := prefix.Addr().Next(); addr.IsValid() && addr.Less(last); addr = addr.Next() {
+		if used[addr] || ranges.contains(addr) {
+			continue
+		}
+
+		return addr.String(), nil
+	}
+
+	return "", fmt.Errorf("%w in %s", ErrNoFreeIP, prefix)
+}
+
+// ReserveIP allocates the next free address to the lease and creates the
+// static lease together with an A record of its name in a single commit,
+// returning the reserved address.
+func (o *OpenWRT) ReserveIP(ctx context.Context, allocation IPAllocation, lease StaticLease) (string, error) {
+	ip, err := o.NextFreeIP(ctx, allocation)
+	if err != nil {
+		return "", err
+	}
+	lease.IP = ip
+
+	if err := lease.Validate(); err != nil {
+		return "", err
+	}
+
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if conflicts := StaticLeaseConflicts(leases, records, lease, ""); len(conflicts) > 0 {
+		return "", fmt.Errorf("%w: lease %s: %s", ErrConflict, lease.Name, strings.Join(conflicts, ", "))
+	}
+
+	if _, err := o.addSection(ctx, "dhcp", "host", lease); err != nil {
+		return "", err
+	}
+
+	if err := o.addA(ctx, DNSRecord{Type: "A", Name: lease.Name, IP: ip}); err != nil {
+		return "", err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return "", err
+	}
+
+	return ip, nil
+}
+
+// usedIPs returns the subnet of the allocation and the addresses in use in it.
+func (o *OpenWRT) usedIPs(ctx context.Context, allocation IPAllocation) (netip.Prefix, map[netip.Addr]bool, error) {
+	var (
+		prefix netip.Prefix
+		err    error
+	)
+	switch {
+	case allocation.Interface != "":
+		prefix, err = o.interfacePrefix(ctx, allocation.Interface)
+		if err == nil && !prefix.IsValid() {
+			err = fmt.Errorf("interface %s has no static ipv4 address", allocation.Interface)
+		}
+	case allocation.CIDR != "":
+		prefix, err = netip.ParsePrefix(allocation.CIDR)
+		if err != nil || !prefix.Addr().Is4() {
+			err = fmt.Errorf("invalid cidr: %s", allocation.CIDR)
+		}
+		prefix = prefix.Masked()
+	default:
+		err = errors.New("interface or cidr is required")
+	}
+	if err != nil {
+		return netip.Prefix{}, nil, err
+	}
+
+	used := make(map[netip.Addr]bool)
+	use := func(ip string) {
+		if addr, err := netip.ParseAddr(strings.Split(ip, "/")[0]); err == nil && prefix.Contains(addr) {
+			used[addr] = true
+		}
+	}
+
+	interfaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return netip.Prefix{}, nil, err
+	}
+	for _, iface := range interfaces {
+		for _, ip := range iface.IPAddr {
+			use(ip)
+		}
+	}
+
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return netip.Prefix{}, nil, err
+	}
+	for _, lease := range leases {
+		use(lease.IP)
+	}
+	for _, record := range records {
+		if record.Type == "A" {
+			use(record.IP)
+		}
+	}
+
+	active, err := o.GetDHCPLeases(ctx)
+	if err != nil {
+		return netip.Prefix{}, nil, err
+	}
+	for _, lease := range active.IPv4 {
+		use(lease.IP)
+	}
+
+	pools, err := o.GetDHCPPools(ctx)
+	if err != nil {
+		return netip.Prefix{}, nil, err
+	}
+	for _, pool := range pools {
+		if pool.Ignore == "1" || interfaces[pool.Interface].Proto != "static" {
+			continue
+		}
+
+		poolPrefix, err := o.interfacePrefix(ctx, pool.Interface)
+		if err != nil || poolPrefix != prefix {
+			continue
+		}
+
+		start, limit := pool.offsets()
+
+		addr := prefix.Addr()
+		for offset := uint64(0); offset < start+limit && addr.IsValid(); offset++ {
+			if offset >= start {
+				used[addr] = true
+			}
+			addr = addr.Next()
+		}
+	}
+
+	return prefix, used, nil
+}
+
+type ipRanges [][2]netip.Addr
+
+// parseIPRanges parses addresses, subnets and first-last ranges.
+func parseIPRanges(values []string) (ipRanges, error) {
+	var ranges ipRanges
+	for _, value := range values {
+		if first, last, ok := strings.Cut(value, "-"); ok {
+			firstAddr, errFirst := netip.ParseAddr(strings.TrimSpace(first))
+			lastAddr, errLast := netip.ParseAddr(strings.TrimSpace(last))
+			if errFirst != nil || errLast != nil || lastAddr.Less(firstAddr) {
+				return nil, fmt.Errorf("invalid range: %s", value)
+			}
+			ranges = append(ranges, [2]netip.Addr{firstAddr, lastAddr})
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cidr: %s", value)
+			}
+			prefix = prefix.Masked()
+			ranges = append(ranges, [2]netip.Addr{prefix.Addr(), lastIP(prefix)})
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip: %s", value)
+		}
+		ranges = append(ranges, [2]netip.Addr{addr, addr})
+	}
+
+	return ranges, nil
+}
+
+func (r ipRanges) contains(addr netip.Addr) bool {
+	for _, ipRange := range r {
+		if !addr.Less(ipRange[0]) && !ipRange[1].Less(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastIP returns the last address of a subnet, its broadcast address for IPv4.
+func lastIP(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Masked().Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
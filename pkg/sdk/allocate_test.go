@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("IP allocation", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "lan", map[string]any{
+			"proto":  "static",
+			"ipaddr": "192.168.1.1/27",
+		})
+		router.AddSection("dhcp", "dhcp", "lan", map[string]any{
+			"interface": "lan",
+			"start":     "10",
+			"limit":     "10",
+		})
+		router.AddSection("dhcp", "host", "", map[string]any{"name": "nas", "mac": "aa:bb:cc:dd:ee:01", "ip": "192.168.1.2"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "printer", "ip": "192.168.1.3"})
+		router.SetOutput("cat "+dnsmasqLeasesFile, "0 aa:bb:cc:dd:ee:02 192.168.1.4 tv *\n")
+	})
+
+	It("skip used addresses", func() {
+		o := newFakeOpenWRT(router)
+		ip, err := o.NextFreeIP(ctx, IPAllocation{Interface: "lan"})
+		Expect(err).To(BeNil())
+		Expect(ip).To(Equal("192.168.1.5"))
+
+		ip, err = o.NextFreeIP(ctx, IPAllocation{CIDR: "192.168.1.0/27", Exclude: []string{"192.168.1.5-192.168.1.8", "192.168.1.9"}})
+		Expect(err).To(BeNil())
+		Expect(ip).To(Equal("192.168.1.20"))
+	})
+
+	It("run out of addresses", func() {
+		o := newFakeOpenWRT(router)
+		_, err := o.NextFreeIP(ctx, IPAllocation{Interface: "lan", Exclude: []string{"192.168.1.0/27"}})
+		Expect(errors.Is(err, ErrNoFreeIP)).To(BeTrue())
+	})
+
+	It("reserve an address", func() {
+		o := newFakeOpenWRT(router)
+		ip, err := o.ReserveIP(ctx, IPAllocation{Interface: "lan"}, StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:dd:ee:02"}})
+		Expect(err).To(BeNil())
+		Expect(ip).To(Equal("192.168.1.5"))
+		Expect(router.Sections("dhcp", "host")).To(ContainElement(HaveKeyWithValue("ip", "192.168.1.5")))
+		Expect(router.Sections("dhcp", "domain")).To(ContainElement(And(
+			HaveKeyWithValue("name", "tv"),
+			HaveKeyWithValue("ip", "192.168.1.5"),
+		)))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+	})
+})
@@ -0,0 +1,288 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GetVLANModel detects how VLANs are configured on the OpenWRT device:
+// routers with a swconfig switch have switch sections in the network config,
+// the others use DSA.
+func (o *OpenWRT) GetVLANModel(ctx context.Context) (VLANModel, error) {
+	switches, err := o.getSwitches(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(switches) > 0 {
+		return VLANModelSwconfig, nil
+	}
+
+	return VLANModelDSA, nil
+}
+
+// GetBridgeVLANs retrieves the bridge VLANs from the OpenWRT device, keyed by
+// section name.
+func (o *OpenWRT) GetBridgeVLANs(ctx context.Context) (map[string]BridgeVLAN, error) {
+	return getSections[BridgeVLAN](ctx, o, "network", "bridge-vlan")
+}
+
+// SetBridgeVLANs adds bridge VLANs to a DSA router, then commits and reloads
+// the network. Nothing is written when a VLAN is invalid, already exists on
+// its bridge or makes a port the PVID of two VLANs.
+func (o *OpenWRT) SetBridgeVLANs(ctx context.Context, vlans []BridgeVLAN) error {
+	currentVLANs, err := o.bridgeVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	bridges, err := o.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, vlan := range vlans {
+		if err := validateBridgeVLAN(bridges, vlan); err != nil {
+			return err
+		}
+
+		if _, ok := findBridgeVLAN(currentVLANs, vlan); ok {
+			return fmt.Errorf("%w: vlan %s already exists on bridge %s", ErrConflict, vlan.VLAN, vlan.Device)
+		}
+		currentVLANs[fmt.Sprintf("new vlan %d", index)] = vlan
+	}
+
+	if err := checkBridgePVIDs(currentVLANs); err != nil {
+		return err
+	}
+
+	for _, vlan := range vlans {
+		if _, err := o.addSection(ctx, "network", "bridge-vlan", vlan); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateBridgeVLANs updates existing bridge VLANs, matched by bridge and
+// VLAN ID, then commits and reloads the network. Empty fields are removed
+// from the VLAN.
+func (o *OpenWRT) UpdateBridgeVLANs(ctx context.Context, updateVLANs []BridgeVLAN) error {
+	currentVLANs, err := o.bridgeVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	bridges, err := o.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateVLANs))
+	var notFound []string
+	for index, vlan := range updateVLANs {
+		if err := validateBridgeVLAN(bridges, vlan); err != nil {
+			return err
+		}
+
+		cfg, ok := findBridgeVLAN(currentVLANs, vlan)
+		if !ok {
+			notFound = append(notFound, vlan.Device+"."+vlan.VLAN)
+			continue
+		}
+		cfgs[index] = cfg
+		currentVLANs[cfg] = vlan
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("bridge vlans not found: %v", notFound)
+	}
+
+	if err := checkBridgePVIDs(currentVLANs); err != nil {
+		return err
+	}
+
+	for index, vlan := range updateVLANs {
+		if err := o.updateSection(ctx, "network", cfgs[index], vlan); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteBridgeVLANs deletes bridge VLANs, matched by bridge and VLAN ID, then
+// commits and reloads the network.
+func (o *OpenWRT) DeleteBridgeVLANs(ctx context.Context, deleteVLANs []BridgeVLAN) error {
+	currentVLANs, err := o.GetBridgeVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, vlan := range deleteVLANs {
+		cfg, ok := findBridgeVLAN(currentVLANs, vlan)
+		if !ok {
+			notFound = append(notFound, vlan.Device+"."+vlan.VLAN)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("bridge vlans not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// Validate checks the fields of a bridge VLAN.
+func (v BridgeVLAN) Validate() error {
+	if err := validateDeviceName("device", v.Device); err != nil {
+		return err
+	}
+
+	if v.VLAN == "" {
+		return fmt.Errorf("vlan is required")
+	}
+
+	if err := validateUint("vlan", v.VLAN, 1, 4094); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, port := range v.Ports {
+		parsed, err := ParseVLANPort(port)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(names, parsed.Name) {
+			return fmt.Errorf("invalid ports: %s is listed twice", parsed.Name)
+		}
+		names = append(names, parsed.Name)
+	}
+
+	return nil
+}
+
+// ParseVLANPort parses a bridge VLAN port written port[:t|:u][*]. Ports
+// without a flag are untagged.
+func ParseVLANPort(port string) (VLANPort, error) {
+	name, flags, _ := strings.Cut(port, ":")
+	if strings.HasSuffix(name, "*") {
+		name, flags = strings.TrimSuffix(name, "*"), flags+"*"
+	}
+
+	parsed := VLANPort{Name: name}
+	switch strings.TrimSuffix(flags, "*") {
+	case "", "u":
+	case "t":
+		parsed.Tagged = true
+	default:
+		return VLANPort{}, fmt.Errorf("invalid port: %s, expected port[:t|:u][*]", port)
+	}
+	parsed.PVID = strings.HasSuffix(flags, "*")
+
+	if err := validateDeviceName("port", name); err != nil {
+		return VLANPort{}, fmt.Errorf("invalid port: %s, expected port[:t|:u][*]", port)
+	}
+
+	return parsed, nil
+}
+
+// String returns the port as written in a bridge-vlan section.
+func (p VLANPort) String() string {
+	port := p.Name + ":u"
+	if p.Tagged {
+		port = p.Name + ":t"
+	}
+
+	if p.PVID {
+		port += "*"
+	}
+
+	return port
+}
+
+// bridgeVLANs retrieves the bridge VLANs, failing on swconfig routers.
+func (o *OpenWRT) bridgeVLANs(ctx context.Context) (map[string]BridgeVLAN, error) {
+	model, err := o.GetVLANModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if model != VLANModelDSA {
+		return nil, fmt.Errorf("bridge vlans are not supported on %s routers", model)
+	}
+
+	return o.GetBridgeVLANs(ctx)
+}
+
+// validateBridgeVLAN checks the VLAN and that its device is a bridge.
+func validateBridgeVLAN(devices map[string]Device, vlan BridgeVLAN) error {
+	if err := vlan.Validate(); err != nil {
+		return err
+	}
+
+	cfg, ok := findDevice(devices, vlan.Device)
+	if !ok || devices[cfg].DeviceType != "bridge" {
+		return fmt.Errorf("invalid device: %s, not a bridge", vlan.Device)
+	}
+
+	return nil
+}
+
+// checkBridgePVIDs checks that every port is the PVID of at most one VLAN of
+// its bridge.
+func checkBridgePVIDs(vlans map[string]BridgeVLAN) error {
+	cfgs := make([]string, 0, len(vlans))
+	for cfg := range vlans {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	pvids := make(map[string]string)
+	for _, cfg := range cfgs {
+		vlan := vlans[cfg]
+		for _, port := range vlan.Ports {
+			parsed, err := ParseVLANPort(port)
+			if err != nil || !parsed.PVID {
+				continue
+			}
+
+			key := vlan.Device + " " + parsed.Name
+			if other, ok := pvids[key]; ok && other != vlan.VLAN {
+				return fmt.Errorf("%w: port %s of bridge %s is the pvid of vlans %s and %s", ErrConflict, parsed.Name, vlan.Device, other, vlan.VLAN)
+			}
+			pvids[key] = vlan.VLAN
+		}
+	}
+
+	return nil
+}
+
+func findBridgeVLAN(vlans map[string]BridgeVLAN, vlan BridgeVLAN) (string, bool) {
+	for cfg, current := range vlans {
+		if current.Device == vlan.Device && current.VLAN == vlan.VLAN {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
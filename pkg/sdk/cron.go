@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression, each field holding the set of
+// matching values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// the day of month and day of week fields restrict the days together
+	// only when both are set, otherwise either of them is enough
+	domAny, dowAny bool
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// parseCron parses a five field cron expression supporting *, values,
+// ranges, lists and steps.
+func parseCron(expr string) (cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return cronSchedule{}, fmt.Errorf("invalid cron: %s, expected 5 fields", expr)
+	}
+
+	sets := make([]map[int]bool, len(fields))
+	for index, field := range fields {
+		set, err := parseCronField(field, cronFields[index].min, cronFields[index].max)
+		if err != nil {
+			return cronSchedule{}, fmt.Errorf("invalid cron %s: %s", cronFields[index].name, field)
+		}
+		sets[index] = set
+	}
+
+	// 7 is another name for sunday
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step: %s", stepPart)
+			}
+		}
+
+		first, last := min, max
+		if rangePart != "*" {
+			firstPart, lastPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if first, err = strconv.Atoi(firstPart); err != nil {
+				return nil, err
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(lastPart); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				last = max
+			}
+		}
+
+		if first < min || last > max || last < first {
+			return nil, fmt.Errorf("out of range: %s", part)
+		}
+
+		for value := first; value <= last; value += step {
+			set[value] = true
+		}
+	}
+
+	return set, nil
+}
+
+// matches reports whether the minute of t matches the schedule.
+func (c cronSchedule) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
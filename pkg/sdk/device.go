@@ -0,0 +1,235 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	deviceTypes  = []string{"bridge", "8021q", "8021ad", "macvlan"}
+	macvlanModes = []string{"private", "vepa", "bridge", "passthru"}
+)
+
+// GetDevices retrieves the network device sections from the OpenWRT device,
+// keyed by section name.
+func (o *OpenWRT) GetDevices(ctx context.Context) (map[string]Device, error) {
+	return getSections[Device](ctx, o, "network", "device")
+}
+
+// SetDevices adds network devices to the OpenWRT device, then commits and
+// reloads the network. Nothing is written when a device is invalid or its
+// name is already configured.
+func (o *OpenWRT) SetDevices(ctx context.Context, devices []Device) error {
+	currentDevices, err := o.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, device := range devices {
+		if err := device.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findDevice(currentDevices, device.Name); ok {
+			return fmt.Errorf("%w: device %s already exists", ErrConflict, device.Name)
+		}
+		currentDevices[fmt.Sprintf("new device %d", index)] = device
+	}
+
+	for _, device := range devices {
+		if _, err := o.addSection(ctx, "network", "device", device); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateDevices updates existing network devices on the OpenWRT device,
+// matched by name, then commits and reloads the network. Empty fields are
+// removed from the device.
+func (o *OpenWRT) UpdateDevices(ctx context.Context, updateDevices []Device) error {
+	currentDevices, err := o.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateDevices))
+	var notFound []string
+	for index, device := range updateDevices {
+		if err := device.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findDevice(currentDevices, device.Name)
+		if !ok {
+			notFound = append(notFound, device.Name)
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("devices not found: %v", notFound)
+	}
+
+	for index, device := range updateDevices {
+		if err := o.updateSection(ctx, "network", cfgs[index], device); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteDevices deletes network devices from the OpenWRT device by name,
+// then commits and reloads the network. A device still used by an
+// interface, a bridge VLAN or a device left in place is not deleted.
+func (o *OpenWRT) DeleteDevices(ctx context.Context, names []string) error {
+	currentDevices, err := o.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	ifaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	vlans, err := o.GetBridgeVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, name := range names {
+		cfg, ok := findDevice(currentDevices, name)
+		if !ok {
+			notFound = append(notFound, name)
+			continue
+		}
+
+		for ifaceName, iface := range ifaces {
+			if iface.Device == name {
+				return fmt.Errorf("%w: device %s is used by interface %s", ErrConflict, name, ifaceName)
+			}
+		}
+
+		for _, vlan := range vlans {
+			if vlan.Device == name {
+				return fmt.Errorf("%w: device %s has bridge vlan %s", ErrConflict, name, vlan.VLAN)
+			}
+		}
+
+		for _, device := range currentDevices {
+			if slices.Contains(names, device.Name) {
+				continue
+			}
+
+			if device.Ifname == name || slices.Contains(device.Ports, name) {
+				return fmt.Errorf("%w: device %s is used by device %s", ErrConflict, name, device.Name)
+			}
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("devices not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// Validate checks the fields of a network device.
+func (d Device) Validate() error {
+	if err := validateDeviceName("name", d.Name); err != nil {
+		return err
+	}
+
+	if d.DeviceType != "" && !slices.Contains(deviceTypes, d.DeviceType) {
+		return fmt.Errorf("invalid type: %s, expected %s", d.DeviceType, strings.Join(deviceTypes, ", "))
+	}
+
+	isVLAN := d.DeviceType == "8021q" || d.DeviceType == "8021ad"
+	if d.DeviceType != "bridge" && (len(d.Ports) > 0 || d.STP != "" || d.VLANFiltering != "") {
+		return fmt.Errorf("ports, stp and vlan_filtering are only set on bridges")
+	}
+
+	if !isVLAN && d.DeviceType != "macvlan" && d.Ifname != "" {
+		return fmt.Errorf("ifname is only set on vlan and macvlan devices")
+	}
+
+	if !isVLAN && d.VID != "" {
+		return fmt.Errorf("vid is only set on vlan devices")
+	}
+
+	if d.DeviceType != "macvlan" && d.Mode != "" {
+		return fmt.Errorf("mode is only set on macvlan devices")
+	}
+
+	for _, port := range d.Ports {
+		if err := validateDeviceName("ports", port); err != nil {
+			return err
+		}
+	}
+
+	if isVLAN || d.DeviceType == "macvlan" {
+		if err := validateDeviceName("ifname", d.Ifname); err != nil {
+			return err
+		}
+	}
+
+	if isVLAN {
+		if d.VID == "" {
+			return fmt.Errorf("vid is required for vlan devices")
+		}
+
+		if err := validateUint("vid", d.VID, 1, 4094); err != nil {
+			return err
+		}
+	}
+
+	if d.Mode != "" && !slices.Contains(macvlanModes, d.Mode) {
+		return fmt.Errorf("invalid mode: %s, expected %s", d.Mode, strings.Join(macvlanModes, ", "))
+	}
+
+	if d.MACAddr != "" {
+		if err := validateMAC("macaddr", d.MACAddr); err != nil {
+			return err
+		}
+	}
+
+	if err := validateUint("mtu", d.MTU, 68, 65535); err != nil {
+		return err
+	}
+
+	if err := validateBool("stp", d.STP); err != nil {
+		return err
+	}
+
+	return validateBool("vlan_filtering", d.VLANFiltering)
+}
+
+func findDevice(devices map[string]Device, name string) (string, bool) {
+	for cfg, device := range devices {
+		if device.Name == name {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
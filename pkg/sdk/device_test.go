@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Devices", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "device", "", map[string]any{
+			"name":  "br-lan",
+			"type":  "bridge",
+			"ports": []string{"lan1", "lan2", "lan3"},
+		})
+		router.AddSection("network", "interface", "lan", map[string]any{"proto": "static", "device": "br-lan", "ipaddr": "192.168.1.1/24"})
+	})
+
+	It("get devices", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.GetDevices(ctx)).To(Equal(map[string]Device{
+			"cfg1": {Type: "device", Name: "br-lan", DeviceType: "bridge", Ports: UciList{"lan1", "lan2", "lan3"}},
+		}))
+	})
+
+	It("create, update and delete a VLAN device and reload the network", func() {
+		o := newFakeOpenWRT(router)
+		device := Device{Name: "br-lan.10", DeviceType: "8021q", Ifname: "br-lan", VID: "10"}
+		macvlan := Device{Name: "wan2", DeviceType: "macvlan", Ifname: "wan", Mode: "private", MACAddr: "02:00:00:00:00:01"}
+		Expect(o.SetDevices(ctx, []Device{device, macvlan})).To(Succeed())
+		Expect(router.Sections("network", "device")).To(HaveLen(3))
+
+		err := o.SetDevices(ctx, []Device{{Name: "br-lan", DeviceType: "bridge"}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		device.MTU = "1496"
+		Expect(o.UpdateDevices(ctx, []Device{device})).To(Succeed())
+		Expect(router.Sections("network", "device")).To(ContainElement(And(
+			HaveKeyWithValue("name", "br-lan.10"),
+			HaveKeyWithValue("mtu", "1496"),
+		)))
+		Expect(o.UpdateDevices(ctx, []Device{{Name: "eth9"}})).To(MatchError("devices not found: [eth9]"))
+
+		err = o.DeleteDevices(ctx, []string{"br-lan"})
+		Expect(err).To(MatchError(ContainSubstring("device br-lan is used by interface lan")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		Expect(o.DeleteDevices(ctx, []string{"br-lan.10", "wan2"})).To(Succeed())
+		Expect(router.Sections("network", "device")).To(HaveLen(1))
+		Expect(router.Commits("network")).To(Equal(3))
+		Expect(router.History()).To(Equal([]string{networkReloadCommand, networkReloadCommand, networkReloadCommand}))
+	})
+
+	It("keep devices used by another device", func() {
+		router.AddSection("network", "device", "", map[string]any{"name": "br-lan.20", "type": "8021q", "ifname": "br-lan", "vid": "20"})
+		o := newFakeOpenWRT(router)
+		Expect(o.DeleteInterfaces(ctx, []string{"lan"})).To(Succeed())
+		err := o.DeleteDevices(ctx, []string{"br-lan"})
+		Expect(err).To(MatchError(ContainSubstring("device br-lan is used by device br-lan.20")))
+		Expect(o.DeleteDevices(ctx, []string{"br-lan.20", "br-lan"})).To(Succeed())
+		Expect(router.Sections("network", "device")).To(BeEmpty())
+	})
+
+	It("validate", func() {
+		Expect(Device{Name: "eth0", MACAddr: "02:00:00:00:00:01", MTU: "9000"}.Validate()).To(Succeed())
+
+		invalidDevices := []Device{
+			{},
+			{Name: "a-very-long-device-name"},
+			{Name: "eth0", DeviceType: "vxlan"},
+			{Name: "eth0", Ports: UciList{"lan1"}},
+			{Name: "br-lan", DeviceType: "bridge", Ports: UciList{"lan 1"}},
+			{Name: "br-lan", DeviceType: "bridge", STP: "yes"},
+			{Name: "br-lan.10", DeviceType: "8021q", Ifname: "br-lan"},
+			{Name: "br-lan.10", DeviceType: "8021q", Ifname: "br-lan", VID: "4095"},
+			{Name: "br-lan.10", DeviceType: "8021ad", VID: "10"},
+			{Name: "wan2", DeviceType: "macvlan", Ifname: "wan", Mode: "source"},
+			{Name: "wan2", DeviceType: "macvlan", Ifname: "wan", VID: "10"},
+			{Name: "eth0", Ifname: "eth1"},
+			{Name: "eth0", Mode: "private"},
+			{Name: "eth0", MACAddr: "02:00:00"},
+			{Name: "eth0", MTU: "40"},
+		}
+		for _, device := range invalidDevices {
+			Expect(device.Validate()).ToNot(Succeed(), "%+v", device)
+		}
+	})
+})
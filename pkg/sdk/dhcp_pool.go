@@ -0,0 +1,390 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DHCP option codes understood by the option helpers.
+const (
+	DHCPOptionRouter       = "3"
+	DHCPOptionDNSServer    = "6"
+	DHCPOptionDomainName   = "15"
+	DHCPOptionNTPServer    = "42"
+	DHCPOptionDomainSearch = "119"
+)
+
+var dhcpOptionNames = map[string]string{
+	"option:router":        DHCPOptionRouter,
+	"option:dns-server":    DHCPOptionDNSServer,
+	"option:domain-name":   DHCPOptionDomainName,
+	"option:ntp-server":    DHCPOptionNTPServer,
+	"option:domain-search": DHCPOptionDomainSearch,
+}
+
+// GetDHCPPools retrieves the DHCP server of every interface from the OpenWRT
+// device, keyed by section name.
+func (o *OpenWRT) GetDHCPPools(ctx context.Context) (map[string]DHCPPool, error) {
+	return getSections[DHCPPool](ctx, o, "dhcp", "dhcp")
+}
+
+// SetDHCPPools adds DHCP servers to the OpenWRT device, each in a section
+// named after its interface. Nothing is written when a pool is invalid, its
+// interface already has a pool or another dhcp section has its name.
+func (o *OpenWRT) SetDHCPPools(ctx context.Context, pools []DHCPPool) error {
+	currentPools, err := o.GetDHCPPools(ctx)
+	if err != nil {
+		return err
+	}
+
+	kinds, err := o.sectionKinds(ctx, "dhcp")
+	if err != nil {
+		return err
+	}
+
+	for _, pool := range pools {
+		if err := o.validateDHCPPool(ctx, pool); err != nil {
+			return err
+		}
+
+		if _, ok := findDHCPPool(currentPools, pool.Interface); ok {
+			return fmt.Errorf("%w: interface %s already has a dhcp pool", ErrConflict, pool.Interface)
+		}
+		currentPools[pool.Interface] = pool
+
+		if err := checkSectionName(kinds, "dhcp", pool.Interface); err != nil {
+			return err
+		}
+		kinds[pool.Interface] = "dhcp"
+	}
+
+	for _, pool := range pools {
+		if err := o.addNamedSection(ctx, "dhcp", "dhcp", pool.Interface, pool); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDHCPPools updates existing DHCP servers on the OpenWRT device,
+// matched by interface. Empty fields are removed from the pool.
+func (o *OpenWRT) UpdateDHCPPools(ctx context.Context, updatePools []DHCPPool) error {
+	currentPools, err := o.GetDHCPPools(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updatePools))
+	var notFound []string
+	for index, pool := range updatePools {
+		if err := o.validateDHCPPool(ctx, pool); err != nil {
+			return err
+		}
+
+		cfg, ok := findDHCPPool(currentPools, pool.Interface)
+		if !ok {
+			notFound = append(notFound, pool.Interface)
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp pools not found: %v", notFound)
+	}
+
+	for index, pool := range updatePools {
+		if err := o.updateSection(ctx, "dhcp", cfgs[index], pool); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDHCPPools deletes the DHCP servers of the interfaces from the OpenWRT device.
+func (o *OpenWRT) DeleteDHCPPools(ctx context.Context, interfaces []string) error {
+	currentPools, err := o.GetDHCPPools(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, iface := range interfaces {
+		cfg, ok := findDHCPPool(currentPools, iface)
+		if !ok {
+			notFound = append(notFound, iface)
+			continue
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp pools not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDHCPOption adds a DHCP option, such as "6,192.168.1.1", to the pool of
+// an interface, replacing the options with the same code.
+func (o *OpenWRT) SetDHCPOption(ctx context.Context, iface, option string) error {
+	if err := validateDHCPOption(option); err != nil {
+		return err
+	}
+	code, _, _ := strings.Cut(option, ",")
+
+	return o.editDHCPOptions(ctx, iface, func(options UciList) UciList {
+		options = removeDHCPOption(options, code)
+		return append(options, option)
+	})
+}
+
+// RemoveDHCPOption removes the DHCP options with the code from the pool of
+// an interface.
+func (o *OpenWRT) RemoveDHCPOption(ctx context.Context, iface, code string) error {
+	return o.editDHCPOptions(ctx, iface, func(options UciList) UciList {
+		return removeDHCPOption(options, code)
+	})
+}
+
+// RouterOption returns the DHCP option announcing the default gateway.
+func RouterOption(ip string) string {
+	return DHCPOptionRouter + "," + ip
+}
+
+// DNSServerOption returns the DHCP option announcing the DNS servers.
+func DNSServerOption(ips ...string) string {
+	return DHCPOptionDNSServer + "," + strings.Join(ips, ",")
+}
+
+// NTPServerOption returns the DHCP option announcing the NTP servers.
+func NTPServerOption(ips ...string) string {
+	return DHCPOptionNTPServer + "," + strings.Join(ips, ",")
+}
+
+// DomainSearchOption returns the DHCP option announcing the DNS search list.
+func DomainSearchOption(domains ...string) string {
+	return DHCPOptionDomainSearch + "," + strings.Join(domains, ",")
+}
+
+// Validate checks the fields of a DHCP pool which do not depend on its interface.
+func (p DHCPPool) Validate() error {
+	if p.Interface == "" {
+		return fmt.Errorf("interface is required")
+	}
+
+	if err := validateUint("start", p.Start, 1, 1<<24); err != nil {
+		return err
+	}
+
+	if err := validateUint("limit", p.Limit, 1, 1<<24); err != nil {
+		return err
+	}
+
+	if err := validateLeaseTime("leasetime", p.LeaseTime); err != nil {
+		return err
+	}
+
+	if err := validateBool("ignore", p.Ignore); err != nil {
+		return err
+	}
+
+	// odhcpd relays DHCPv6 and RA only
+	if p.DHCPv4 != "" && !slices.Contains([]string{"disabled", "server"}, p.DHCPv4) {
+		return fmt.Errorf("invalid dhcpv4: %s, expected disabled or server", p.DHCPv4)
+	}
+
+	modes := [][]string{
+		{"dhcpv6", p.DHCPv6},
+		{"ra", p.RA},
+	}
+	for _, mode := range modes {
+		if mode[1] != "" && !slices.Contains([]string{"disabled", "server", "relay", "hybrid"}, mode[1]) {
+			return fmt.Errorf("invalid %s: %s, expected disabled, server, relay or hybrid", mode[0], mode[1])
+		}
+	}
+
+	for _, option := range p.DHCPOption {
+		if err := validateDHCPOption(option); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDHCPPool checks the pool and that start and limit fit inside the
+// subnet of its interface, when the interface has a static address.
+func (o *OpenWRT) validateDHCPPool(ctx context.Context, pool DHCPPool) error {
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+
+	prefix, err := o.interfacePrefix(ctx, pool.Interface)
+	if err != nil || !prefix.IsValid() {
+		return err
+	}
+
+	start, limit := pool.offsets()
+
+	// offsets are relative to the network address, leaving the broadcast out
+	size := uint64(1) << (32 - prefix.Bits())
+	if start+limit > size-1 {
+		return fmt.Errorf("dhcp pool %d+%d does not fit in %s", start, limit, prefix)
+	}
+
+	return nil
+}
+
+// interfacePrefix returns the first IPv4 subnet of a static interface, or an
+// invalid prefix when the interface has no static address.
+func (o *OpenWRT) interfacePrefix(ctx context.Context, iface string) (netip.Prefix, error) {
+	section, err := o.GetInterface(ctx, iface)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	if section.Proto != "static" || len(section.IPAddr) == 0 {
+		return netip.Prefix{}, nil
+	}
+
+	return parseIPv4Prefix(section.IPAddr[0], section.Netmask)
+}
+
+// parseIPv4Prefix parses an address in CIDR notation or with a dotted netmask.
+func parseIPv4Prefix(ipaddr, netmask string) (netip.Prefix, error) {
+	if strings.Contains(ipaddr, "/") {
+		prefix, err := netip.ParsePrefix(ipaddr)
+		if err != nil || !prefix.Addr().Is4() {
+			return netip.Prefix{}, fmt.Errorf("invalid ipaddr: %s", ipaddr)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(ipaddr)
+	if err != nil || !addr.Is4() {
+		return netip.Prefix{}, fmt.Errorf("invalid ipaddr: %s", ipaddr)
+	}
+
+	bits := 32
+	if netmask != "" {
+		if bits, err = netmaskBits(netmask); err != nil {
+			return netip.Prefix{}, err
+		}
+	}
+
+	return netip.PrefixFrom(addr, bits).Masked(), nil
+}
+
+func netmaskBits(netmask string) (int, error) {
+	mask, err := netip.ParseAddr(netmask)
+	if err != nil || !mask.Is4() {
+		return 0, fmt.Errorf("invalid netmask: %s", netmask)
+	}
+
+	bytes := mask.As4()
+	value := uint32(bytes[0])<<24 | uint32(bytes[1])<<16 | uint32(bytes[2])<<8 | uint32(bytes[3])
+	bits := 0
+	for value&(1<<31) != 0 {
+		bits++
+		value <<= 1
+	}
+
+	if value != 0 {
+		return 0, fmt.Errorf("invalid netmask: %s", netmask)
+	}
+
+	return bits, nil
+}
+
+// offsets returns the first address of the pool, relative to the network
+// address, and the number of addresses, using the OpenWRT defaults.
+func (p DHCPPool) offsets() (uint64, uint64) {
+	start, limit := uint64(100), uint64(150)
+	if p.Start != "" {
+		start, _ = strconv.ParseUint(p.Start, 10, 64)
+	}
+	if p.Limit != "" {
+		limit, _ = strconv.ParseUint(p.Limit, 10, 64)
+	}
+
+	return start, limit
+}
+
+func (o *OpenWRT) editDHCPOptions(ctx context.Context, iface string, edit func(UciList) UciList) error {
+	pools, err := o.GetDHCPPools(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, ok := findDHCPPool(pools, iface)
+	if !ok {
+		return fmt.Errorf("%w: dhcp pool of %s", ErrSectionNotFound, iface)
+	}
+
+	pool := pools[cfg]
+	pool.DHCPOption = edit(slices.Clone(pool.DHCPOption))
+	if err := o.updateSection(ctx, "dhcp", cfg, pool); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func removeDHCPOption(options UciList, code string) UciList {
+	code = dhcpOptionCode(code)
+	return slices.DeleteFunc(options, func(option string) bool {
+		current, _, _ := strings.Cut(option, ",")
+		return dhcpOptionCode(current) == code
+	})
+}
+
+// dhcpOptionCode returns the numeric code of the options known by name.
+func dhcpOptionCode(code string) string {
+	if number, ok := dhcpOptionNames[code]; ok {
+		return number
+	}
+
+	return code
+}
+
+func findDHCPPool(pools map[string]DHCPPool, iface string) (string, bool) {
+	for cfg, pool := range pools {
+		if pool.Interface == iface {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
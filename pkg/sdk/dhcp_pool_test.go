@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("DHCP pools", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "lan", map[string]any{
+			"proto":   "static",
+			"ipaddr":  "192.168.1.1",
+			"netmask": "255.255.255.0",
+		})
+		router.AddSection("network", "interface", "iot", map[string]any{
+			"proto":  "static",
+			"ipaddr": []any{"10.0.20.1/28"},
+		})
+		router.AddSection("dhcp", "dhcp", "lan", map[string]any{
+			"interface": "lan",
+			"start":     "100",
+			"limit":     "150",
+		})
+	})
+
+	It("create a pool", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.SetDHCPPools(ctx, []DHCPPool{
+			{Interface: "iot", Start: "2", Limit: "12", LeaseTime: "1h"},
+		})).To(Succeed())
+
+		pools, err := o.GetDHCPPools(ctx)
+		Expect(err).To(BeNil())
+		Expect(pools).To(HaveKeyWithValue("iot", DHCPPool{
+			Type:      "dhcp",
+			Interface: "iot",
+			Start:     "2",
+			Limit:     "12",
+			LeaseTime: "1h",
+		}))
+	})
+
+	It("keep a section of another type named like the interface", func() {
+		router.AddSection("dhcp", "tag", "iot", map[string]any{"dhcp_option": []any{"3,10.0.20.2"}})
+		o := newFakeOpenWRT(router)
+		err := o.SetDHCPPools(ctx, []DHCPPool{{Interface: "iot", Start: "2", Limit: "12"}})
+		Expect(err).To(MatchError(ContainSubstring("dhcp.iot already exists as a tag section")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(router.Sections("dhcp", "tag")).To(ConsistOf(HaveKeyWithValue(".name", "iot")))
+		Expect(router.Commits("dhcp")).To(Equal(0))
+	})
+
+	It("reject a pool outside the subnet", func() {
+		o := newFakeOpenWRT(router)
+		err := o.SetDHCPPools(ctx, []DHCPPool{
+			{Interface: "iot", Start: "2", Limit: "14"},
+		})
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(Equal("dhcp pool 2+14 does not fit in 10.0.20.0/28"))
+
+		err = o.UpdateDHCPPools(ctx, []DHCPPool{
+			{Interface: "lan", Start: "200", Limit: "100"},
+		})
+		Expect(err).ToNot(BeNil())
+		Expect(router.Commits("dhcp")).To(Equal(0))
+	})
+
+	It("edit options", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.SetDHCPOption(ctx, "lan", RouterOption("192.168.1.254"))).To(Succeed())
+		Expect(o.SetDHCPOption(ctx, "lan", DNSServerOption("192.168.1.2", "192.168.1.3"))).To(Succeed())
+		Expect(o.SetDHCPOption(ctx, "lan", "option:router,192.168.1.1")).To(Succeed())
+		Expect(router.Sections("dhcp", "dhcp")[0]).To(HaveKeyWithValue("dhcp_option", []any{
+			"6,192.168.1.2,192.168.1.3",
+			"option:router,192.168.1.1",
+		}))
+
+		Expect(o.RemoveDHCPOption(ctx, "lan", DHCPOptionRouter)).To(Succeed())
+		Expect(router.Sections("dhcp", "dhcp")[0]).To(HaveKeyWithValue("dhcp_option", []any{
+			"6,192.168.1.2,192.168.1.3",
+		}))
+	})
+
+	It("validate", func() {
+		Expect(DHCPPool{Interface: "lan", DHCPv4: "server", DHCPv6: "hybrid", RA: "relay"}.Validate()).To(Succeed())
+		Expect(DHCPPool{Interface: "lan", DHCPv4: "relay"}.Validate()).To(MatchError("invalid dhcpv4: relay, expected disabled or server"))
+		Expect(DHCPPool{Interface: "lan", DHCPv4: "hybrid"}.Validate()).ToNot(Succeed())
+		Expect(DHCPPool{Interface: "lan", RA: "proxy"}.Validate()).ToNot(Succeed())
+	})
+
+	It("delete a pool", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.DeleteDHCPPools(ctx, []string{"lan"})).To(Succeed())
+		Expect(router.Sections("dhcp", "dhcp")).To(BeEmpty())
+		Expect(o.DeleteDHCPPools(ctx, []string{"lan"})).ToNot(Succeed())
+	})
+})
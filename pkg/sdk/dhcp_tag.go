@@ -0,0 +1,369 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var dhcpMatchKinds = []string{"mac", "vendorclass", "userclass"}
+
+// GetDHCPTags retrieves the DHCP tags from the OpenWRT device, keyed by
+// section name.
+func (o *OpenWRT) GetDHCPTags(ctx context.Context) (map[string]DHCPTag, error) {
+	return getSections[DHCPTag](ctx, o, "dhcp", "tag")
+}
+
+// SetDHCPTags adds DHCP tags to the OpenWRT device, each in a section named
+// after the tag. Nothing is written when a tag is invalid or its name is
+// already used by a section of the dhcp config.
+func (o *OpenWRT) SetDHCPTags(ctx context.Context, tags []DHCPTag) error {
+	kinds, err := o.sectionKinds(ctx, "dhcp")
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err := tag.Validate(); err != nil {
+			return err
+		}
+
+		if err := checkSectionName(kinds, "dhcp", tag.Name); err != nil {
+			return err
+		}
+		kinds[tag.Name] = "tag"
+	}
+
+	for _, tag := range tags {
+		if err := o.addNamedSection(ctx, "dhcp", "tag", tag.Name, tag); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDHCPTags updates existing DHCP tags on the OpenWRT device, matched
+// by name. Empty fields are removed from the tag.
+func (o *OpenWRT) UpdateDHCPTags(ctx context.Context, updateTags []DHCPTag) error {
+	currentTags, err := o.GetDHCPTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound []string
+	for _, tag := range updateTags {
+		if err := tag.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := currentTags[tag.Name]; !ok {
+			notFound = append(notFound, tag.Name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp tags not found: %v", notFound)
+	}
+
+	for _, tag := range updateTags {
+		if err := o.updateSection(ctx, "dhcp", tag.Name, tag); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDHCPTags deletes DHCP tags from the OpenWRT device. A tag still set
+// on a static lease is not deleted.
+func (o *OpenWRT) DeleteDHCPTags(ctx context.Context, names []string) error {
+	currentTags, err := o.GetDHCPTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	leases, err := o.GetStaticLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound []string
+	for _, name := range names {
+		if _, ok := currentTags[name]; !ok {
+			notFound = append(notFound, name)
+			continue
+		}
+
+		for _, lease := range leases {
+			if slices.Contains(lease.Tag, name) {
+				return fmt.Errorf("%w: dhcp tag %s is set on lease %s", ErrConflict, name, lease.Name)
+			}
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp tags not found: %v", notFound)
+	}
+
+	for _, name := range names {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", name}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AttachDHCPTag sets an existing DHCP tag on the static lease called lease.
+func (o *OpenWRT) AttachDHCPTag(ctx context.Context, lease, tag string) error {
+	tags, err := o.GetDHCPTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tags[tag]; !ok {
+		return fmt.Errorf("%w: dhcp tag %s", ErrSectionNotFound, tag)
+	}
+
+	return o.editLeaseTags(ctx, lease, func(tags UciList) UciList {
+		if slices.Contains(tags, tag) {
+			return tags
+		}
+		return append(tags, tag)
+	})
+}
+
+// DetachDHCPTag removes a DHCP tag from the static lease called lease.
+func (o *OpenWRT) DetachDHCPTag(ctx context.Context, lease, tag string) error {
+	return o.editLeaseTags(ctx, lease, func(tags UciList) UciList {
+		return slices.DeleteFunc(tags, func(current string) bool { return current == tag })
+	})
+}
+
+// GetDHCPMatches retrieves the mac, vendorclass and userclass sections from
+// the OpenWRT device, keyed by section name.
+func (o *OpenWRT) GetDHCPMatches(ctx context.Context) (map[string]DHCPMatch, error) {
+	return getSections[DHCPMatch](ctx, o, "dhcp", dhcpMatchKinds...)
+}
+
+// SetDHCPMatches adds match sections to the OpenWRT device.
+func (o *OpenWRT) SetDHCPMatches(ctx context.Context, matches []DHCPMatch) error {
+	currentMatches, err := o.GetDHCPMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, match := range matches {
+		if err := match.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findDHCPMatch(currentMatches, match); ok {
+			return fmt.Errorf("%w: %s %s is already matched", ErrConflict, match.Type, match.value())
+		}
+		currentMatches[fmt.Sprintf("new match %d", index)] = match
+	}
+
+	for _, match := range matches {
+		if _, err := o.addSection(ctx, "dhcp", match.Type, match); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDHCPMatches updates existing match sections on the OpenWRT device,
+// matched by type and matched value. Empty fields are removed from the section.
+func (o *OpenWRT) UpdateDHCPMatches(ctx context.Context, updateMatches []DHCPMatch) error {
+	currentMatches, err := o.GetDHCPMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateMatches))
+	var notFound []string
+	for index, match := range updateMatches {
+		if err := match.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findDHCPMatch(currentMatches, match)
+		if !ok {
+			notFound = append(notFound, match.Type+" "+match.value())
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp matches not found: %v", notFound)
+	}
+
+	for index, match := range updateMatches {
+		if err := o.updateSection(ctx, "dhcp", cfgs[index], match); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDHCPMatches deletes match sections from the OpenWRT device, matched
+// by type and matched value.
+func (o *OpenWRT) DeleteDHCPMatches(ctx context.Context, deleteMatches []DHCPMatch) error {
+	currentMatches, err := o.GetDHCPMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, match := range deleteMatches {
+		cfg, ok := findDHCPMatch(currentMatches, match)
+		if !ok {
+			notFound = append(notFound, match.Type+" "+match.value())
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dhcp matches not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a DHCP tag.
+func (t DHCPTag) Validate() error {
+	if err := validateUciName("name", t.Name); err != nil {
+		return err
+	}
+
+	for _, option := range t.DHCPOption {
+		if err := validateDHCPOption(option); err != nil {
+			return err
+		}
+	}
+
+	return validateBool("force", t.Force)
+}
+
+// Validate checks the fields of a match section.
+func (m DHCPMatch) Validate() error {
+	if !slices.Contains(dhcpMatchKinds, m.Type) {
+		return fmt.Errorf("invalid type: %s, expected %s", m.Type, strings.Join(dhcpMatchKinds, ", "))
+	}
+
+	values := map[string]string{"mac": m.MAC, "vendorclass": m.VendorClass, "userclass": m.UserClass}
+	for _, kind := range dhcpMatchKinds {
+		switch value := values[kind]; {
+		case kind == m.Type && value == "":
+			return fmt.Errorf("%s is required", kind)
+		case kind != m.Type && value != "":
+			return fmt.Errorf("invalid %s: only %s is matched by a %s section", kind, m.Type, m.Type)
+		}
+	}
+
+	if m.Type == "mac" {
+		if err := validateMAC("mac", m.MAC); err != nil {
+			return err
+		}
+	}
+
+	if err := validateUciName("networkid", m.NetworkID); err != nil {
+		return err
+	}
+
+	for _, option := range m.DHCPOption {
+		if err := validateDHCPOption(option); err != nil {
+			return err
+		}
+	}
+
+	return validateBool("force", m.Force)
+}
+
+// value returns the MAC address or class matched by the section.
+func (m DHCPMatch) value() string {
+	switch m.Type {
+	case "mac":
+		return m.MAC
+	case "vendorclass":
+		return m.VendorClass
+	default:
+		return m.UserClass
+	}
+}
+
+func (o *OpenWRT) editLeaseTags(ctx context.Context, name string, edit func(UciList) UciList) error {
+	leases, err := o.GetStaticLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, ok := findStaticLease(leases, name)
+	if !ok {
+		return fmt.Errorf("%w: lease %s", ErrSectionNotFound, name)
+	}
+
+	lease := leases[cfg]
+	lease.Tag = edit(slices.Clone(lease.Tag))
+	if err := o.updateSection(ctx, "dhcp", cfg, lease); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func findDHCPMatch(matches map[string]DHCPMatch, match DHCPMatch) (string, bool) {
+	for cfg, current := range matches {
+		if current.Type == match.Type && strings.EqualFold(current.value(), match.value()) {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
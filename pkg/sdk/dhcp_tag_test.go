@@ -0,0 +1,119 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("DHCP tags", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("dhcp", "tag", "gw2", map[string]any{"dhcp_option": []any{"3,192.168.1.2"}})
+		router.AddSection("dhcp", "host", "", map[string]any{"name": "nas", "mac": "aa:bb:cc:dd:ee:01"})
+		router.AddSection("dhcp", "vendorclass", "", map[string]any{"vendorclass": "MSFT", "networkid": "windows"})
+	})
+
+	It("get tags and matches", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.GetDHCPTags(ctx)).To(Equal(map[string]DHCPTag{
+			"gw2": {Type: "tag", Name: "gw2", DHCPOption: UciList{"3,192.168.1.2"}},
+		}))
+		Expect(o.GetDHCPMatches(ctx)).To(Equal(map[string]DHCPMatch{
+			"cfg2": {Type: "vendorclass", VendorClass: "MSFT", NetworkID: "windows"},
+		}))
+	})
+
+	It("keep a section of another type named like a new tag", func() {
+		router.AddSection("dhcp", "dhcp", "lan", map[string]any{"interface": "lan", "start": "100"})
+		o := newFakeOpenWRT(router)
+
+		err := o.SetDHCPTags(ctx, []DHCPTag{{Name: "iot"}, {Name: "lan"}})
+		Expect(err).To(MatchError(ContainSubstring("dhcp.lan already exists as a dhcp section")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		err = o.addNamedSection(ctx, "dhcp", "tag", "lan", DHCPTag{Name: "lan"})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		Expect(router.Sections("dhcp", "dhcp")).To(ConsistOf(HaveKeyWithValue(".name", "lan")))
+		Expect(router.Sections("dhcp", "tag")).To(HaveLen(1))
+	})
+
+	It("create, update and delete a tag", func() {
+		o := newFakeOpenWRT(router)
+		tag := DHCPTag{Name: "dns2", DHCPOption: UciList{DNSServerOption("192.168.1.3")}}
+		Expect(o.SetDHCPTags(ctx, []DHCPTag{tag})).To(Succeed())
+		Expect(router.Sections("dhcp", "tag")).To(ContainElement(And(
+			HaveKeyWithValue(".name", "dns2"),
+			HaveKeyWithValue("dhcp_option", []any{"6,192.168.1.3"}),
+		)))
+
+		err := o.SetDHCPTags(ctx, []DHCPTag{tag})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		tag.Force = "1"
+		Expect(o.UpdateDHCPTags(ctx, []DHCPTag{tag})).To(Succeed())
+		Expect(router.Sections("dhcp", "tag")).To(ContainElement(HaveKeyWithValue("force", "1")))
+
+		Expect(o.DeleteDHCPTags(ctx, []string{"dns2"})).To(Succeed())
+		Expect(router.Sections("dhcp", "tag")).To(HaveLen(1))
+		Expect(o.DeleteDHCPTags(ctx, []string{"dns2"})).To(MatchError("dhcp tags not found: [dns2]"))
+		Expect(router.Commits("dhcp")).To(Equal(3))
+	})
+
+	It("attach and detach a tag", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.AttachDHCPTag(ctx, "nas", "gw2")).To(Succeed())
+		Expect(o.AttachDHCPTag(ctx, "nas", "gw2")).To(Succeed())
+		Expect(router.Sections("dhcp", "host")).To(ConsistOf(HaveKeyWithValue("tag", []any{"gw2"})))
+
+		err := o.DeleteDHCPTags(ctx, []string{"gw2"})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		err = o.AttachDHCPTag(ctx, "nas", "missing")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+
+		Expect(o.DetachDHCPTag(ctx, "nas", "gw2")).To(Succeed())
+		Expect(router.Sections("dhcp", "host")).To(ConsistOf(Not(HaveKey("tag"))))
+	})
+
+	It("create, update and delete a match", func() {
+		o := newFakeOpenWRT(router)
+		match := DHCPMatch{Type: "mac", MAC: "aa:bb:cc:*:*:*", NetworkID: "gw2"}
+		Expect(o.SetDHCPMatches(ctx, []DHCPMatch{match})).To(Succeed())
+		Expect(router.Sections("dhcp", "mac")).To(ConsistOf(And(
+			HaveKeyWithValue("mac", "aa:bb:cc:*:*:*"),
+			HaveKeyWithValue("networkid", "gw2"),
+		)))
+
+		err := o.SetDHCPMatches(ctx, []DHCPMatch{match})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		match.NetworkID = "dns2"
+		Expect(o.UpdateDHCPMatches(ctx, []DHCPMatch{match})).To(Succeed())
+		Expect(router.Sections("dhcp", "mac")).To(ConsistOf(HaveKeyWithValue("networkid", "dns2")))
+
+		Expect(o.DeleteDHCPMatches(ctx, []DHCPMatch{match, {Type: "vendorclass", VendorClass: "MSFT"}})).To(Succeed())
+		Expect(router.Sections("dhcp", "mac")).To(BeEmpty())
+		Expect(router.Sections("dhcp", "vendorclass")).To(BeEmpty())
+		Expect(o.DeleteDHCPMatches(ctx, []DHCPMatch{match})).To(MatchError("dhcp matches not found: [mac aa:bb:cc:*:*:*]"))
+	})
+
+	It("validate", func() {
+		Expect(DHCPTag{Name: "gw-2"}.Validate()).ToNot(Succeed())
+		Expect(DHCPTag{Name: "gw2", DHCPOption: UciList{"3"}}.Validate()).ToNot(Succeed())
+		Expect(DHCPMatch{Type: "mac", MAC: "foobar", NetworkID: "gw2"}.Validate()).ToNot(Succeed())
+		Expect(DHCPMatch{Type: "mac", MAC: "aa:bb:cc:dd:ee:01", UserClass: "x", NetworkID: "gw2"}.Validate()).ToNot(Succeed())
+		Expect(DHCPMatch{Type: "userclass", UserClass: "voip", NetworkID: "gw2"}.Validate()).To(Succeed())
+		Expect(StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:dd:ee:03"}, Tag: UciList{"gw 2"}}.Validate()).ToNot(Succeed())
+	})
+})
@@ -2,41 +2,65 @@ package sdk
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
 	"strings"
 )
 
 // GetDNSRecords retrieves all DNS records from the OpenWRT device.
 func (o *OpenWRT) GetDNSRecords(ctx context.Context) (map[string]DNSRecord, error) {
-	result, err := o.lucirpc.Uci(ctx, "get_all", []string{"dhcp"})
+	sections, err := getSections[dnsSection](ctx, o, "dhcp", "domain", "cname", "srvhost", "mxhost")
 	if err != nil {
 		return nil, err
 	}
 
-	var records map[string]DNSRecord
-	err = json.Unmarshal([]byte(result), &records)
-	if err != nil {
-		return nil, err
-	}
-
-	for key, record := range records {
-		switch record.Type {
+	records := make(map[string]DNSRecord)
+	for key, section := range sections {
+		switch section.Type {
 		case "domain":
+			recordType := "A"
+			if strings.Contains(section.IP, ":") {
+				recordType = "AAAA"
+			}
 			records[key] = DNSRecord{
-				Type: "A",
-				IP:   record.IP,
-				Name: record.Name,
+				Type:     recordType,
+				IP:       section.IP,
+				Name:     section.Name,
+				Owner:    section.Owner,
+				Instance: section.Instance,
 			}
 		case "cname":
 			records[key] = DNSRecord{
-				Type:   "CNAME",
-				CName:  record.CName,
-				Target: record.Target,
+				Type:     "CNAME",
+				CName:    section.CName,
+				Target:   section.Target,
+				Owner:    section.Owner,
+				Instance: section.Instance,
+			}
+		case "srvhost":
+			records[key] = DNSRecord{
+				Type:     "SRV",
+				Name:     section.Srv,
+				Target:   section.Target,
+				Port:     section.Port,
+				Priority: section.Class,
+				Weight:   section.Weight,
+				Owner:    section.Owner,
+				Instance: section.Instance,
+			}
+		case "mxhost":
+			records[key] = DNSRecord{
+				Type:     "MX",
+				Name:     section.Domain,
+				Target:   section.Relay,
+				Priority: section.Pref,
+				Owner:    section.Owner,
+				Instance: section.Instance,
 			}
 		default:
 			// it does not care about other types
-			delete(records, key)
 		}
 	}
 
@@ -44,19 +68,16 @@ func (o *OpenWRT) GetDNSRecords(ctx context.Context) (map[string]DNSRecord, erro
 }
 
 // SetDNSRecords adds new DNS records to the OpenWRT device.
+// Several A records sharing the same name are served round-robin by dnsmasq,
+// so adding a record never replaces the existing members of a name.
 func (o *OpenWRT) SetDNSRecords(ctx context.Context, records []DNSRecord) error {
+	if err := validateDNSRecords(records); err != nil {
+		return err
+	}
+
 	for _, record := range records {
-		switch strings.ToUpper(record.Type) {
-		case "A":
-			if err := o.addA(ctx, record); err != nil {
-				return err
-			}
-		case "CNAME":
-			if err := o.addCName(ctx, record); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("invalid record type: %s", record.Type)
+		if err := o.addRecord(ctx, record); err != nil {
+			return err
 		}
 	}
 
@@ -68,42 +89,123 @@ func (o *OpenWRT) SetDNSRecords(ctx context.Context, records []DNSRecord) error
 }
 
 // UpdateDNSRecords updates existing DNS records on the OpenWRT device.
+// Records are matched by type and name, and every current member of a name is
+// replaced by the update records carrying that name, so passing several A
+// records with the same name sets the whole round-robin group at once.
+// Use ReplaceDNSRecords to change a single member of a group.
 func (o *OpenWRT) UpdateDNSRecords(ctx context.Context, updateRecords []DNSRecord) error {
 	currentRecords, err := o.GetDNSRecords(ctx)
 	if err != nil {
 		return err
 	}
 
-	for cfg, currentRecord := range currentRecords {
-		for index, updateRecord := range updateRecords {
-			if updateRecord.Type == "A" && updateRecord.Name == currentRecord.Name {
-				_, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg})
-				if err != nil {
-					return err
-				}
+	if err := o.updateDNSRecords(ctx, currentRecords, updateRecords); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateDNSRecords replaces the current members of the names of
+// updateRecords without committing, once all of the records are valid and
+// their names found.
+func (o *OpenWRT) updateDNSRecords(ctx context.Context, currentRecords map[string]DNSRecord, updateRecords []DNSRecord) error {
+	if err := validateDNSRecords(updateRecords); err != nil {
+		return err
+	}
+
+	var (
+		groups    [][]DNSRecord
+		cfgGroups [][]string
+		notFound  []string
+	)
+	for _, updateRecord := range updateRecords {
+		index := slices.IndexFunc(groups, func(group []DNSRecord) bool {
+			return group[0].sameName(updateRecord)
+		})
+		if index >= 0 {
+			groups[index] = append(groups[index], updateRecord)
+			continue
+		}
 
-				if err := o.addA(ctx, updateRecord); err != nil {
-					return err
-				}
-				updateRecords = append(updateRecords[:index], updateRecords[index+1:]...)
+		cfgs := matchDNSRecords(currentRecords, updateRecord.withoutValue())
+		if len(cfgs) == 0 {
+			notFound = append(notFound, updateRecord.label())
+			continue
+		}
+
+		groups = append(groups, []DNSRecord{updateRecord})
+		cfgGroups = append(cfgGroups, cfgs)
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("records not found: %v", notFound)
+	}
+
+	for index, group := range groups {
+		for _, cfg := range cfgGroups[index] {
+			if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+				return err
 			}
+		}
+
+		for _, updateRecord := range group {
+			if err := o.addRecord(ctx, updateRecord); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReplaceDNSRecords replaces individual DNS records on the OpenWRT device.
+// The old record of each change must identify exactly one current record,
+// which usually means setting its value (IP or target) when the name has
+// several members. Nothing is written when a new record is invalid.
+func (o *OpenWRT) ReplaceDNSRecords(ctx context.Context, changes []DNSRecordChange) error {
+	currentRecords, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return err
+	}
 
-			if updateRecord.Type == "CNAME" && updateRecord.CName == currentRecord.CName {
-				_, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg})
-				if err != nil {
-					return err
-				}
+	cfgs := make([]string, 0, len(changes))
+	var notFound []string
+	for _, change := range changes {
+		if err := change.New.Validate(); err != nil {
+			return err
+		}
 
-				if err := o.addCName(ctx, updateRecord); err != nil {
-					return err
-				}
-				updateRecords = append(updateRecords[:index], updateRecords[index+1:]...)
+		matches := matchDNSRecords(currentRecords, change.Old)
+		switch len(matches) {
+		case 0:
+			notFound = append(notFound, change.Old.label())
+		case 1:
+			if slices.Contains(cfgs, matches[0]) {
+				return fmt.Errorf("record replaced more than once: %s", change.Old.label())
 			}
+			cfgs = append(cfgs, matches[0])
+		default:
+			return fmt.Errorf("record matches %d entries: %s", len(matches), change.Old.label())
 		}
 	}
 
-	if len(updateRecords) > 0 {
-		return fmt.Errorf("records not found: %v", updateRecords)
+	if len(notFound) > 0 {
+		return fmt.Errorf("records not found: %v", notFound)
+	}
+
+	for index, change := range changes {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfgs[index]}); err != nil {
+			return err
+		}
+
+		if err := o.addRecord(ctx, change.New); err != nil {
+			return err
+		}
 	}
 
 	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
@@ -114,30 +216,49 @@ func (o *OpenWRT) UpdateDNSRecords(ctx context.Context, updateRecords []DNSRecor
 }
 
 // DeleteDNSRecords deletes DNS records from the OpenWRT device.
+// A record without a value (IP or target) deletes every member of its name,
+// otherwise only the members with that exact value are deleted. Setting the
+// owner restricts the deletion to the records created by that owner.
 func (o *OpenWRT) DeleteDNSRecords(ctx context.Context, deleteRecords []DNSRecord) error {
 	currentRecords, err := o.GetDNSRecords(ctx)
 	if err != nil {
 		return err
 	}
 
-	for cfg, currentRecord := range currentRecords {
-		for index, deleteRecord := range deleteRecords {
-			if (deleteRecord.Type == "A" && deleteRecord.Name == currentRecord.Name) ||
-				(deleteRecord.Type == "CNAME" && deleteRecord.CName == currentRecord.CName) {
-				_, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg})
-				if err != nil {
-					return err
-				}
-				deleteRecords = append(deleteRecords[:index], deleteRecords[index+1:]...)
-			}
-		}
+	if err := o.deleteDNSRecords(ctx, currentRecords, deleteRecords); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ChangeDNSRecords deletes records, as DeleteDNSRecords does, and adds new
+// ones, as SetDNSRecords does, in a single commit. Nothing is written when a
+// new record is invalid or a deleted record is not found.
+func (o *OpenWRT) ChangeDNSRecords(ctx context.Context, deleteRecords, addRecords []DNSRecord) error {
+	if err := validateDNSRecords(addRecords); err != nil {
+		return err
+	}
+
+	currentRecords, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return err
 	}
 
-	if len(deleteRecords) > 0 {
-		return fmt.Errorf("records not found: %v", deleteRecords)
+	if err := o.deleteDNSRecords(ctx, currentRecords, deleteRecords); err != nil {
+		return err
+	}
+
+	for _, record := range addRecords {
+		if err := o.addRecord(ctx, record); err != nil {
+			return err
+		}
 	}
 
-	// should we remove even when records not found?
 	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
 		return err
 	}
@@ -145,17 +266,191 @@ func (o *OpenWRT) DeleteDNSRecords(ctx context.Context, deleteRecords []DNSRecor
 	return nil
 }
 
-func (o *OpenWRT) addA(ctx context.Context, record DNSRecord) error {
-	if record.Type != "a" && record.Type != "A" {
-		return fmt.Errorf("invalid record type: %s", record.Type)
+// deleteDNSRecords deletes the current records matched by deleteRecords
+// without committing, once all of them are found.
+func (o *OpenWRT) deleteDNSRecords(ctx context.Context, currentRecords map[string]DNSRecord, deleteRecords []DNSRecord) error {
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, deleteRecord := range deleteRecords {
+		matches := matchDNSRecords(currentRecords, deleteRecord)
+		if len(matches) == 0 {
+			notFound = append(notFound, deleteRecord.label())
+			continue
+		}
+
+		for _, cfg := range matches {
+			if !slices.Contains(cfgs, cfg) {
+				cfgs = append(cfgs, cfg)
+			}
+		}
 	}
 
-	if record.Name == "" {
-		return fmt.Errorf("name is required")
+	if len(notFound) > 0 {
+		return fmt.Errorf("records not found: %v", notFound)
 	}
 
-	if record.IP == "" {
-		return fmt.Errorf("ip is required")
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchDNSRecords returns the sorted sections of records identified by record.
+func matchDNSRecords(records map[string]DNSRecord, record DNSRecord) []string {
+	var cfgs []string
+	for cfg, current := range records {
+		if record.Identifies(current) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	slices.Sort(cfgs)
+
+	return cfgs
+}
+
+// sameName reports whether both records have the same type and name, names
+// being case-insensitive as in DNS.
+func (r DNSRecord) sameName(other DNSRecord) bool {
+	if !strings.EqualFold(r.Type, other.Type) {
+		return false
+	}
+
+	switch strings.ToUpper(r.Type) {
+	case "A", "AAAA", "SRV", "MX":
+		return strings.EqualFold(r.Name, other.Name)
+	case "CNAME":
+		return strings.EqualFold(r.CName, other.CName)
+	default:
+		return false
+	}
+}
+
+// Identifies reports whether current is the record described by r.
+// The value, the owner and the instance take part in the identity only when
+// set on r.
+func (r DNSRecord) Identifies(current DNSRecord) bool {
+	if !r.sameName(current) {
+		return false
+	}
+
+	if r.Owner != "" && r.Owner != current.Owner {
+		return false
+	}
+
+	if r.Instance != "" && r.Instance != current.Instance {
+		return false
+	}
+
+	switch strings.ToUpper(r.Type) {
+	case "A", "AAAA":
+		return r.IP == "" || r.IP == current.IP
+	case "CNAME", "MX":
+		return r.Target == "" || r.Target == current.Target
+	case "SRV":
+		return (r.Target == "" || r.Target == current.Target) && (r.Port == "" || r.Port == current.Port)
+	default:
+		return false
+	}
+}
+
+// withoutValue returns a copy of r identifying every member of its name.
+func (r DNSRecord) withoutValue() DNSRecord {
+	r.IP = ""
+	r.Target = ""
+	r.Port = ""
+	r.Priority = ""
+	r.Weight = ""
+	return r
+}
+
+// label returns the type, name and value of r in errors, leaving out the
+// options added to records over time.
+func (r DNSRecord) label() string {
+	return fmt.Sprintf("{%s %s %s %s %s}", r.Type, r.IP, r.Name, r.CName, r.Target)
+}
+
+// Validate checks the fields a DNS record of its type requires.
+func (r DNSRecord) Validate() error {
+	switch recordType := strings.ToUpper(r.Type); recordType {
+	case "A", "AAAA":
+		if r.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+
+		if r.IP == "" {
+			return fmt.Errorf("ip is required")
+		}
+
+		ip, err := netip.ParseAddr(r.IP)
+		if err != nil || (recordType == "A") != ip.Is4() {
+			return fmt.Errorf("invalid ip for %s record: %s", recordType, r.IP)
+		}
+	case "CNAME":
+		if r.Type != "cname" && r.Type != "CNAME" {
+			return fmt.Errorf("invalid record type: %s", r.Type)
+		}
+
+		if r.CName == "" {
+			return fmt.Errorf("cname is required")
+		}
+
+		if r.Target == "" {
+			return fmt.Errorf("target is required")
+		}
+	case "SRV", "MX":
+		if r.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+
+		if r.Target == "" {
+			return fmt.Errorf("target is required")
+		}
+
+		if port, err := strconv.Atoi(r.Port); recordType == "SRV" && (err != nil || port < 1 || port > 65535) {
+			return fmt.Errorf("invalid port: %s", r.Port)
+		}
+	default:
+		return fmt.Errorf("invalid record type: %s", r.Type)
+	}
+
+	return nil
+}
+
+// validateDNSRecords checks every record, so that nothing is staged when one
+// of them is invalid.
+func validateDNSRecords(records []DNSRecord) error {
+	for _, record := range records {
+		if err := record.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *OpenWRT) addRecord(ctx context.Context, record DNSRecord) error {
+	switch strings.ToUpper(record.Type) {
+	case "A", "AAAA":
+		return o.addA(ctx, record)
+	case "CNAME":
+		return o.addCName(ctx, record)
+	case "SRV":
+		return o.addSRV(ctx, record)
+	case "MX":
+		return o.addMX(ctx, record)
+	default:
+		return fmt.Errorf("invalid record type: %s", record.Type)
+	}
+}
+
+func (o *OpenWRT) addA(ctx context.Context, record DNSRecord) error {
+	if err := record.Validate(); err != nil {
+		return err
 	}
 
 	cfg, err := o.lucirpc.Uci(ctx, "add", []string{"dhcp", "domain"})
@@ -171,20 +466,12 @@ func (o *OpenWRT) addA(ctx context.Context, record DNSRecord) error {
 		return err
 	}
 
-	return nil
+	return o.setRecordOptions(ctx, cfg, record)
 }
 
 func (o *OpenWRT) addCName(ctx context.Context, record DNSRecord) error {
-	if record.Type != "cname" && record.Type != "CNAME" {
-		return fmt.Errorf("invalid record type: %s", record.Type)
-	}
-
-	if record.CName == "" {
-		return fmt.Errorf("cname is required")
-	}
-
-	if record.Target == "" {
-		return fmt.Errorf("target is required")
+	if err := record.Validate(); err != nil {
+		return err
 	}
 
 	cfg, err := o.lucirpc.Uci(ctx, "add", []string{"dhcp", "cname"})
@@ -200,5 +487,97 @@ func (o *OpenWRT) addCName(ctx context.Context, record DNSRecord) error {
 		return err
 	}
 
+	return o.setRecordOptions(ctx, cfg, record)
+}
+
+// setRecordOptions sets the options shared by every record type.
+func (o *OpenWRT) setRecordOptions(ctx context.Context, cfg string, record DNSRecord) error {
+	if record.Owner != "" {
+		if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, "owner", record.Owner}); err != nil {
+			return err
+		}
+	}
+
+	if record.Instance != "" {
+		if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, "instance", record.Instance}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (o *OpenWRT) addSRV(ctx context.Context, record DNSRecord) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	cfg, err := o.lucirpc.Uci(ctx, "add", []string{"dhcp", "srvhost"})
+	if err != nil {
+		return err
+	}
+
+	options := [][]string{
+		{"srv", record.Name},
+		{"target", record.Target},
+		{"port", record.Port},
+		{"class", record.Priority},
+		{"weight", record.Weight},
+	}
+	for _, option := range options {
+		if option[1] == "" {
+			continue
+		}
+
+		if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, option[0], option[1]}); err != nil {
+			return err
+		}
+	}
+
+	return o.setRecordOptions(ctx, cfg, record)
+}
+
+func (o *OpenWRT) addMX(ctx context.Context, record DNSRecord) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	cfg, err := o.lucirpc.Uci(ctx, "add", []string{"dhcp", "mxhost"})
+	if err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, "domain", record.Name}); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, "relay", record.Target}); err != nil {
+		return err
+	}
+
+	if record.Priority != "" {
+		if _, err := o.lucirpc.Uci(ctx, "set", []string{"dhcp", cfg, "pref", record.Priority}); err != nil {
+			return err
+		}
+	}
+
+	return o.setRecordOptions(ctx, cfg, record)
+}
+
+// dnsSection represents a dhcp config section holding a DNS record
+type dnsSection struct {
+	Type     string `json:".type"`
+	IP       string `json:"ip"`
+	Name     string `json:"name"`
+	CName    string `json:"cname"`
+	Target   string `json:"target"`
+	Srv      string `json:"srv"`
+	Port     string `json:"port"`
+	Class    string `json:"class"`
+	Weight   string `json:"weight"`
+	Domain   string `json:"domain"`
+	Relay    string `json:"relay"`
+	Pref     string `json:"pref"`
+	Owner    string `json:"owner"`
+	Instance string `json:"instance"`
+}
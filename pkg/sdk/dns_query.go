@@ -0,0 +1,212 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+const (
+	// DNSConflictDuplicate reports the same record defined more than once
+	DNSConflictDuplicate = "duplicate"
+	// DNSConflictCName reports a CNAME sharing its name with other records
+	DNSConflictCName = "cname"
+)
+
+// FindDNSRecords retrieves the DNS records matching the query from the OpenWRT device.
+func (o *OpenWRT) FindDNSRecords(ctx context.Context, query DNSRecordQuery) (map[string]DNSRecord, error) {
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterDNSRecords(records, query)
+}
+
+// ReverseLookupDNS returns every name resolving to the IP address, either
+// through an A or AAAA record or through a chain of CNAME records.
+func (o *OpenWRT) ReverseLookupDNS(ctx context.Context, ip string) ([]string, error) {
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReverseLookupDNSRecords(records, ip)
+}
+
+// FindDNSRecordConflicts reports duplicated records and CNAME records sharing
+// their name with other records on the OpenWRT device.
+func (o *OpenWRT) FindDNSRecordConflicts(ctx context.Context) ([]DNSRecordConflict, error) {
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return DNSRecordConflicts(records), nil
+}
+
+// FilterDNSRecords returns the records matching the query.
+func FilterDNSRecords(records map[string]DNSRecord, query DNSRecordQuery) (map[string]DNSRecord, error) {
+	var (
+		ip     netip.Addr
+		prefix netip.Prefix
+		err    error
+	)
+	if query.IP != "" {
+		if ip, err = netip.ParseAddr(query.IP); err != nil {
+			return nil, fmt.Errorf("invalid ip: %s", query.IP)
+		}
+	}
+
+	if query.CIDR != "" {
+		if prefix, err = netip.ParsePrefix(query.CIDR); err != nil {
+			return nil, fmt.Errorf("invalid cidr: %s", query.CIDR)
+		}
+	}
+
+	suffix := normalizeDNSName(query.Suffix)
+	result := make(map[string]DNSRecord)
+	for cfg, record := range records {
+		name := normalizeDNSName(record.recordName())
+
+		if query.Type != "" && !strings.EqualFold(query.Type, record.Type) {
+			continue
+		}
+
+		if query.Name != "" && normalizeDNSName(query.Name) != name {
+			continue
+		}
+
+		if suffix != "" && name != suffix && !strings.HasSuffix(name, "."+suffix) {
+			continue
+		}
+
+		if query.Owner != "" && query.Owner != record.Owner {
+			continue
+		}
+
+		if query.IP != "" || query.CIDR != "" {
+			addr, err := netip.ParseAddr(record.IP)
+			if err != nil {
+				continue
+			}
+
+			if query.IP != "" && addr != ip {
+				continue
+			}
+
+			if query.CIDR != "" && !prefix.Contains(addr) {
+				continue
+			}
+		}
+
+		result[cfg] = record
+	}
+
+	return result, nil
+}
+
+// ReverseLookupDNSRecords returns the sorted names resolving to the IP address.
+func ReverseLookupDNSRecords(records map[string]DNSRecord, ip string) ([]string, error) {
+	byIP, err := FilterDNSRecords(records, DNSRecordQuery{IP: ip})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, record := range byIP {
+		names = appendDNSName(names, record.Name)
+	}
+
+	// follow CNAME chains until no new name shows up
+	for index := 0; index < len(names); index++ {
+		for _, record := range records {
+			if strings.EqualFold(record.Type, "CNAME") && normalizeDNSName(record.Target) == names[index] {
+				names = appendDNSName(names, record.CName)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// DNSRecordConflicts returns the duplicated records and the CNAME records
+// sharing their name with other records, sorted by name.
+func DNSRecordConflicts(records map[string]DNSRecord) []DNSRecordConflict {
+	cfgs := make([]string, 0, len(records))
+	for cfg := range records {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	var (
+		conflicts []DNSRecordConflict
+		names     []string
+		values    []string
+		byName    = make(map[string][]string)
+		byValue   = make(map[string][]string)
+	)
+	for _, cfg := range cfgs {
+		record := records[cfg]
+		name := normalizeDNSName(record.recordName())
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], cfg)
+
+		value := strings.ToUpper(record.Type) + " " + name + " " + record.IP + normalizeDNSName(record.Target) + " " + record.Port
+		if _, ok := byValue[value]; !ok {
+			values = append(values, value)
+		}
+		byValue[value] = append(byValue[value], cfg)
+	}
+
+	for _, value := range values {
+		sections := byValue[value]
+		if len(sections) > 1 {
+			name := normalizeDNSName(records[sections[0]].recordName())
+			conflicts = append(conflicts, DNSRecordConflict{Kind: DNSConflictDuplicate, Name: name, Sections: sections})
+		}
+	}
+
+	for _, name := range names {
+		sections := byName[name]
+		hasCName := slices.ContainsFunc(sections, func(cfg string) bool {
+			return strings.EqualFold(records[cfg].Type, "CNAME")
+		})
+		if hasCName && len(sections) > 1 {
+			conflicts = append(conflicts, DNSRecordConflict{Kind: DNSConflictCName, Name: name, Sections: sections})
+		}
+	}
+
+	slices.SortStableFunc(conflicts, func(a, b DNSRecordConflict) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return conflicts
+}
+
+// recordName returns the name a record answers for.
+func (r DNSRecord) recordName() string {
+	if strings.EqualFold(r.Type, "CNAME") {
+		return r.CName
+	}
+
+	return r.Name
+}
+
+func appendDNSName(names []string, name string) []string {
+	name = normalizeDNSName(name)
+	if slices.Contains(names, name) {
+		return names
+	}
+
+	return append(names, name)
+}
+
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
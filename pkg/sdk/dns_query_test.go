@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DNS query", func() {
+	records := map[string]DNSRecord{
+		"a": {Type: "A", Name: "nas.home.lan", IP: "192.168.1.10", Owner: "k8s"},
+		"b": {Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+		"c": {Type: "A", Name: "printer.lan", IP: "192.168.2.20"},
+		"d": {Type: "CNAME", CName: "files.home.lan", Target: "nas.home.lan"},
+		"e": {Type: "CNAME", CName: "www.home.lan", Target: "files.home.lan", Owner: "k8s"},
+		"f": {Type: "CNAME", CName: "printer.lan", Target: "nas.home.lan"},
+	}
+
+	Context("filter", func() {
+		It("by name", func() {
+			result, err := FilterDNSRecords(records, DNSRecordQuery{Name: "NAS.home.lan."})
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveKey("a"))
+			Expect(result).To(HaveKey("b"))
+			Expect(result).To(HaveLen(2))
+		})
+
+		It("by suffix and type", func() {
+			result, err := FilterDNSRecords(records, DNSRecordQuery{Suffix: "home.lan", Type: "cname"})
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveKey("d"))
+			Expect(result).To(HaveKey("e"))
+			Expect(result).To(HaveLen(2))
+		})
+
+		It("by cidr", func() {
+			result, err := FilterDNSRecords(records, DNSRecordQuery{CIDR: "192.168.2.0/24"})
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(map[string]DNSRecord{"c": records["c"]}))
+		})
+
+		It("by owner", func() {
+			result, err := FilterDNSRecords(records, DNSRecordQuery{Owner: "k8s"})
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveKey("a"))
+			Expect(result).To(HaveKey("e"))
+			Expect(result).To(HaveLen(2))
+		})
+
+		It("invalid cidr", func() {
+			_, err := FilterDNSRecords(records, DNSRecordQuery{CIDR: "foobar"})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("invalid cidr: foobar"))
+		})
+	})
+
+	It("reverse lookup", func() {
+		names, err := ReverseLookupDNSRecords(records, "192.168.1.10")
+		Expect(err).To(BeNil())
+		Expect(names).To(Equal([]string{"files.home.lan", "nas.home.lan", "printer.lan", "www.home.lan"}))
+	})
+
+	It("conflicts", func() {
+		Expect(DNSRecordConflicts(records)).To(Equal([]DNSRecordConflict{
+			{Kind: DNSConflictDuplicate, Name: "nas.home.lan", Sections: []string{"a", "b"}},
+			{Kind: DNSConflictCName, Name: "printer.lan", Sections: []string{"c", "f"}},
+		}))
+	})
+})
@@ -0,0 +1,416 @@
+package sdk
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExportDNSZone retrieves all DNS records from the OpenWRT device as an
+// RFC 1035 zone file fragment.
+func (o *OpenWRT) ExportDNSZone(ctx context.Context) (string, error) {
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatDNSZone(records), nil
+}
+
+// ExportHosts retrieves the A and AAAA records from the OpenWRT device in
+// /etc/hosts format.
+func (o *OpenWRT) ExportHosts(ctx context.Context) (string, error) {
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatHosts(records), nil
+}
+
+// PlanDNSImport compares the records to import with the records on the
+// OpenWRT device without changing anything. Imported records are
+// authoritative for their type and name: a name whose current members differ
+// from the imported ones is planned as an update of the whole group.
+func (o *OpenWRT) PlanDNSImport(ctx context.Context, records []DNSRecord) (DNSImportPlan, error) {
+	currentRecords, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return DNSImportPlan{}, err
+	}
+
+	var (
+		plan   DNSImportPlan
+		groups [][]DNSRecord
+	)
+	for _, record := range records {
+		index := slices.IndexFunc(groups, func(group []DNSRecord) bool {
+			return group[0].sameName(record)
+		})
+		if index < 0 {
+			groups = append(groups, nil)
+			index = len(groups) - 1
+		}
+
+		if !slices.ContainsFunc(groups[index], record.sameValue) {
+			groups[index] = append(groups[index], record)
+		}
+	}
+
+	for _, group := range groups {
+		var current []DNSRecord
+		for _, cfg := range matchDNSRecords(currentRecords, group[0].withoutValue()) {
+			current = append(current, currentRecords[cfg])
+		}
+
+		switch {
+		case len(current) == 0:
+			plan.Create = append(plan.Create, group...)
+		case sameDNSValues(current, group):
+			plan.Unchanged = append(plan.Unchanged, group...)
+		default:
+			plan.Update = append(plan.Update, group...)
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyDNSImport creates and updates the records of the plan on the OpenWRT
+// device in a single commit. Nothing is written when a record is invalid.
+func (o *OpenWRT) ApplyDNSImport(ctx context.Context, plan DNSImportPlan) error {
+	if len(plan.Create) == 0 && len(plan.Update) == 0 {
+		return nil
+	}
+
+	if err := validateDNSRecords(plan.Create); err != nil {
+		return err
+	}
+
+	currentRecords, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := o.updateDNSRecords(ctx, currentRecords, plan.Update); err != nil {
+		return err
+	}
+
+	for _, record := range plan.Create {
+		if err := o.addRecord(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FormatDNSZone formats the records as an RFC 1035 zone file fragment with
+// absolute names, sorted by name and type.
+func FormatDNSZone(records map[string]DNSRecord) string {
+	var builder strings.Builder
+	for _, record := range sortedDNSRecords(records) {
+		var data string
+		switch record.Type {
+		case "A", "AAAA":
+			data = record.IP
+		case "CNAME":
+			data = absoluteDNSName(record.Target)
+		case "SRV":
+			data = fmt.Sprintf("%s %s %s %s", cmp.Or(record.Priority, "0"), cmp.Or(record.Weight, "0"),
+				record.Port, absoluteDNSName(record.Target))
+		case "MX":
+			data = fmt.Sprintf("%s %s", cmp.Or(record.Priority, "0"), absoluteDNSName(record.Target))
+		default:
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%s\tIN\t%s\t%s\n", absoluteDNSName(record.recordName()), record.Type, data)
+	}
+
+	return builder.String()
+}
+
+// FormatHosts formats the A and AAAA records in /etc/hosts format, one line
+// per address listing every name pointing at it.
+func FormatHosts(records map[string]DNSRecord) string {
+	var (
+		addrs []string
+		names = make(map[string][]string)
+	)
+	for _, record := range sortedDNSRecords(records) {
+		if record.Type != "A" && record.Type != "AAAA" {
+			continue
+		}
+
+		if _, ok := names[record.IP]; !ok {
+			addrs = append(addrs, record.IP)
+		}
+		names[record.IP] = appendDNSName(names[record.IP], record.Name)
+	}
+
+	slices.SortFunc(addrs, func(a, b string) int {
+		addrA, errA := netip.ParseAddr(a)
+		addrB, errB := netip.ParseAddr(b)
+		if errA != nil || errB != nil {
+			return strings.Compare(a, b)
+		}
+		return addrA.Compare(addrB)
+	})
+
+	var builder strings.Builder
+	for _, addr := range addrs {
+		fmt.Fprintf(&builder, "%s\t%s\n", addr, strings.Join(names[addr], " "))
+	}
+
+	return builder.String()
+}
+
+// ParseDNSZone parses A, AAAA, CNAME, SRV and MX records from an RFC 1035
+// zone file. Relative names are completed with origin, or with the $ORIGIN
+// directive of the zone. Lines holding other record types are returned as
+// skipped.
+func ParseDNSZone(r io.Reader, origin string) ([]DNSRecord, []string, error) {
+	var (
+		records []DNSRecord
+		skipped []string
+		owner   string
+		number  int
+	)
+	origin = strings.TrimSuffix(origin, ".")
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		if index := strings.Index(line, ";"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "$ORIGIN":
+			if len(fields) != 2 {
+				return nil, nil, fmt.Errorf("line %d: invalid $ORIGIN", number)
+			}
+			origin = strings.TrimSuffix(fields[1], ".")
+			continue
+		case "$TTL":
+			continue
+		case "$INCLUDE", "$GENERATE":
+			skipped = append(skipped, scanner.Text())
+			continue
+		}
+
+		if strings.ContainsAny(line, "()") {
+			return nil, nil, fmt.Errorf("line %d: multi-line records are not supported", number)
+		}
+
+		// a line starting with a blank reuses the previous owner name
+		if line[0] != ' ' && line[0] != '\t' {
+			owner = zoneName(fields[0], origin)
+			fields = fields[1:]
+		}
+
+		if owner == "" {
+			return nil, nil, fmt.Errorf("line %d: missing owner name", number)
+		}
+
+		// skip the optional TTL and class in any order
+		for len(fields) > 0 {
+			if _, err := strconv.ParseUint(fields[0], 10, 32); err == nil {
+				fields = fields[1:]
+				continue
+			}
+			if slices.Contains([]string{"IN", "CH", "HS"}, strings.ToUpper(fields[0])) {
+				fields = fields[1:]
+				continue
+			}
+			break
+		}
+
+		if len(fields) == 0 {
+			return nil, nil, fmt.Errorf("line %d: missing record type", number)
+		}
+
+		record, ok, err := zoneRecord(owner, strings.ToUpper(fields[0]), fields[1:], origin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		if !ok {
+			skipped = append(skipped, scanner.Text())
+			continue
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return records, skipped, nil
+}
+
+// ParseHosts parses A and AAAA records from a file in /etc/hosts format.
+// Lines for loopback addresses are returned as skipped.
+func ParseHosts(r io.Reader) ([]DNSRecord, []string, error) {
+	var (
+		records []DNSRecord
+		skipped []string
+		number  int
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, nil, fmt.Errorf("line %d: missing host name", number)
+		}
+
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid ip: %s", number, fields[0])
+		}
+
+		if addr.IsLoopback() {
+			skipped = append(skipped, scanner.Text())
+			continue
+		}
+
+		recordType := "A"
+		if addr.Is6() {
+			recordType = "AAAA"
+		}
+
+		for _, name := range fields[1:] {
+			records = append(records, DNSRecord{Type: recordType, Name: name, IP: addr.String()})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return records, skipped, nil
+}
+
+func zoneRecord(owner, recordType string, data []string, origin string) (DNSRecord, bool, error) {
+	expected := map[string]int{"A": 1, "AAAA": 1, "CNAME": 1, "SRV": 4, "MX": 2}
+	count, ok := expected[recordType]
+	if !ok {
+		return DNSRecord{}, false, nil
+	}
+
+	if len(data) != count {
+		return DNSRecord{}, false, fmt.Errorf("invalid %s record data: %s", recordType, strings.Join(data, " "))
+	}
+
+	switch recordType {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(data[0])
+		if err != nil || (recordType == "A") != addr.Is4() {
+			return DNSRecord{}, false, fmt.Errorf("invalid ip for %s record: %s", recordType, data[0])
+		}
+		return DNSRecord{Type: recordType, Name: owner, IP: addr.String()}, true, nil
+	case "CNAME":
+		return DNSRecord{Type: recordType, CName: owner, Target: zoneName(data[0], origin)}, true, nil
+	case "SRV":
+		return DNSRecord{
+			Type:     recordType,
+			Name:     owner,
+			Priority: data[0],
+			Weight:   data[1],
+			Port:     data[2],
+			Target:   zoneName(data[3], origin),
+		}, true, nil
+	default:
+		return DNSRecord{Type: recordType, Name: owner, Priority: data[0], Target: zoneName(data[1], origin)}, true, nil
+	}
+}
+
+// zoneName returns the name without its trailing dot, completing relative
+// names with the origin.
+func zoneName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case origin == "":
+		return name
+	default:
+		return name + "." + origin
+	}
+}
+
+func absoluteDNSName(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// sameValue reports whether both records of the same name hold the same data.
+func (r DNSRecord) sameValue(other DNSRecord) bool {
+	return r.IP == other.IP &&
+		normalizeDNSName(r.Target) == normalizeDNSName(other.Target) &&
+		r.Port == other.Port &&
+		cmp.Or(r.Priority, "0") == cmp.Or(other.Priority, "0") &&
+		cmp.Or(r.Weight, "0") == cmp.Or(other.Weight, "0")
+}
+
+func sameDNSValues(current, imported []DNSRecord) bool {
+	for _, record := range current {
+		if !slices.ContainsFunc(imported, record.sameValue) {
+			return false
+		}
+	}
+
+	for _, record := range imported {
+		if !slices.ContainsFunc(current, record.sameValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedDNSRecords(records map[string]DNSRecord) []DNSRecord {
+	sorted := make([]DNSRecord, 0, len(records))
+	for _, record := range records {
+		sorted = append(sorted, record)
+	}
+
+	slices.SortFunc(sorted, func(a, b DNSRecord) int {
+		return cmp.Or(
+			strings.Compare(normalizeDNSName(a.recordName()), normalizeDNSName(b.recordName())),
+			strings.Compare(a.Type, b.Type),
+			strings.Compare(a.IP, b.IP),
+			strings.Compare(a.Target, b.Target),
+			strings.Compare(a.Port, b.Port),
+		)
+	})
+
+	return sorted
+}
@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	mocks "github.com/renanqts/openwrt-sdk/internal/mocks/openwrt"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("DNS zone", func() {
+	records := map[string]DNSRecord{
+		"a": {Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+		"b": {Type: "AAAA", Name: "nas.home.lan", IP: "fd00::10"},
+		"c": {Type: "A", Name: "files.home.lan", IP: "192.168.1.10"},
+		"d": {Type: "CNAME", CName: "www.home.lan", Target: "nas.home.lan"},
+		"e": {Type: "SRV", Name: "_http._tcp.home.lan", Target: "nas.home.lan", Port: "80", Priority: "10", Weight: "5"},
+		"f": {Type: "MX", Name: "home.lan", Target: "mail.home.lan", Priority: "10"},
+	}
+
+	It("formats a zone", func() {
+		Expect(FormatDNSZone(records)).To(Equal(strings.Join([]string{
+			"_http._tcp.home.lan.\tIN\tSRV\t10 5 80 nas.home.lan.",
+			"files.home.lan.\tIN\tA\t192.168.1.10",
+			"home.lan.\tIN\tMX\t10 mail.home.lan.",
+			"nas.home.lan.\tIN\tA\t192.168.1.10",
+			"nas.home.lan.\tIN\tAAAA\tfd00::10",
+			"www.home.lan.\tIN\tCNAME\tnas.home.lan.",
+			"",
+		}, "\n")))
+	})
+
+	It("formats hosts", func() {
+		Expect(FormatHosts(records)).To(Equal("192.168.1.10\tfiles.home.lan nas.home.lan\nfd00::10\tnas.home.lan\n"))
+	})
+
+	It("parses a zone", func() {
+		zone := `$ORIGIN home.lan.
+$TTL 3600
+@       IN SOA ns.home.lan. admin.home.lan. 1 7200 3600 1209600 3600
+nas     IN A    192.168.1.10 ; storage
+        IN AAAA fd00::10
+www 300 IN CNAME nas
+_http._tcp IN SRV 10 5 80 nas.home.lan.
+@          MX  10 mail
+`
+		parsed, skipped, err := ParseDNSZone(strings.NewReader(zone), "")
+		Expect(err).To(BeNil())
+		Expect(skipped).To(HaveLen(1))
+		Expect(parsed).To(Equal([]DNSRecord{
+			{Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+			{Type: "AAAA", Name: "nas.home.lan", IP: "fd00::10"},
+			{Type: "CNAME", CName: "www.home.lan", Target: "nas.home.lan"},
+			{Type: "SRV", Name: "_http._tcp.home.lan", Target: "nas.home.lan", Port: "80", Priority: "10", Weight: "5"},
+			{Type: "MX", Name: "home.lan", Target: "mail.home.lan", Priority: "10"},
+		}))
+	})
+
+	It("rejects invalid zone data", func() {
+		_, _, err := ParseDNSZone(strings.NewReader("nas.home.lan. IN A fd00::10\n"), "")
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(Equal("line 1: invalid ip for A record: fd00::10"))
+	})
+
+	It("parses hosts", func() {
+		hosts := `127.0.0.1 localhost
+# servers
+192.168.1.10 nas.home.lan nas
+fd00::10     nas.home.lan
+`
+		parsed, skipped, err := ParseHosts(strings.NewReader(hosts))
+		Expect(err).To(BeNil())
+		Expect(skipped).To(Equal([]string{"127.0.0.1 localhost"}))
+		Expect(parsed).To(Equal([]DNSRecord{
+			{Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+			{Type: "A", Name: "nas", IP: "192.168.1.10"},
+			{Type: "AAAA", Name: "nas.home.lan", IP: "fd00::10"},
+		}))
+	})
+
+	It("plans an import", func() {
+		ctx := context.Background()
+		mockCtrl := gomock.NewController(GinkgoT())
+		mockLuciRPC := mocks.NewMockLuciRPC(mockCtrl)
+
+		currentJson, err := json.Marshal(map[string]dnsSection{
+			"x": {Type: "domain", Name: "nas.home.lan", IP: "192.168.1.10"},
+			"y": {Type: "domain", Name: "printer.home.lan", IP: "192.168.1.20"},
+			"z": {Type: "srvhost", Srv: "_http._tcp.home.lan", Target: "nas.home.lan", Port: "80"},
+		})
+		Expect(err).To(BeNil())
+		mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+
+		o := OpenWRT{
+			lucirpc: mockLuciRPC,
+		}
+		plan, err := o.PlanDNSImport(ctx, []DNSRecord{
+			{Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+			{Type: "A", Name: "Printer.home.lan", IP: "192.168.1.21"},
+			{Type: "A", Name: "printer.home.lan", IP: "192.168.1.22"},
+			{Type: "SRV", Name: "_http._tcp.home.lan", Target: "nas.home.lan.", Port: "80", Priority: "0"},
+			{Type: "CNAME", CName: "www.home.lan", Target: "nas.home.lan"},
+		})
+		Expect(err).To(BeNil())
+		Expect(plan).To(Equal(DNSImportPlan{
+			Create: []DNSRecord{
+				{Type: "CNAME", CName: "www.home.lan", Target: "nas.home.lan"},
+			},
+			Update: []DNSRecord{
+				{Type: "A", Name: "Printer.home.lan", IP: "192.168.1.21"},
+				{Type: "A", Name: "printer.home.lan", IP: "192.168.1.22"},
+			},
+			Unchanged: []DNSRecord{
+				{Type: "A", Name: "nas.home.lan", IP: "192.168.1.10"},
+				{Type: "SRV", Name: "_http._tcp.home.lan", Target: "nas.home.lan.", Port: "80", Priority: "0"},
+			},
+		}))
+	})
+
+	It("applies an import in one commit", func() {
+		ctx := context.Background()
+		mockCtrl := gomock.NewController(GinkgoT())
+		mockLuciRPC := mocks.NewMockLuciRPC(mockCtrl)
+
+		currentJson, err := json.Marshal(map[string]dnsSection{
+			"x": {Type: "domain", Name: "printer.home.lan", IP: "192.168.1.20"},
+		})
+		Expect(err).To(BeNil())
+		gomock.InOrder(
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "x"}).Return("", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "domain"}).Return("y", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "y", "name", "printer.home.lan"}).Return("", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "y", "ip", "192.168.1.21"}).Return("", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "cname"}).Return("z", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "z", "cname", "www.home.lan"}).Return("", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "z", "target", "printer.home.lan"}).Return("", nil),
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil),
+		)
+
+		o := OpenWRT{
+			lucirpc: mockLuciRPC,
+		}
+		Expect(o.ApplyDNSImport(ctx, DNSImportPlan{
+			Create: []DNSRecord{{Type: "CNAME", CName: "www.home.lan", Target: "printer.home.lan"}},
+			Update: []DNSRecord{{Type: "A", Name: "printer.home.lan", IP: "192.168.1.21"}},
+		})).To(Succeed())
+
+		Expect(o.ApplyDNSImport(ctx, DNSImportPlan{
+			Create: []DNSRecord{{Type: "CNAME", CName: "www.home.lan"}},
+			Update: []DNSRecord{{Type: "A", Name: "printer.home.lan", IP: "192.168.1.21"}},
+		})).To(MatchError("target is required"))
+	})
+})
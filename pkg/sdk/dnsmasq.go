@@ -0,0 +1,133 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetDnsmasqConfigs retrieves every dnsmasq instance from the OpenWRT device,
+// keyed by section name.
+func (o *OpenWRT) GetDnsmasqConfigs(ctx context.Context) (map[string]DnsmasqConfig, error) {
+	return getSections[DnsmasqConfig](ctx, o, "dhcp", "dnsmasq")
+}
+
+// GetDnsmasqConfig retrieves a dnsmasq instance by its section name. An empty
+// instance selects the only instance of routers running a single dnsmasq.
+func (o *OpenWRT) GetDnsmasqConfig(ctx context.Context, instance string) (DnsmasqConfig, error) {
+	instance, err := o.dnsmasqInstance(ctx, instance)
+	if err != nil {
+		return DnsmasqConfig{}, err
+	}
+
+	var cfg DnsmasqConfig
+	if err := o.getSection(ctx, "dhcp", instance, &cfg); err != nil {
+		return DnsmasqConfig{}, err
+	}
+
+	if cfg.Type != "dnsmasq" {
+		return DnsmasqConfig{}, fmt.Errorf("%w: dhcp.%s is not a dnsmasq instance", ErrSectionNotFound, instance)
+	}
+
+	return cfg, nil
+}
+
+// UpdateDnsmasqConfig updates the settings of a dnsmasq instance on the
+// OpenWRT device. Empty fields are removed from the instance, letting dnsmasq
+// use its defaults, so callers usually modify the result of GetDnsmasqConfig.
+func (o *OpenWRT) UpdateDnsmasqConfig(ctx context.Context, instance string, cfg DnsmasqConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	instance, err := o.dnsmasqInstance(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	// make sure the section is a dnsmasq instance before writing to it
+	if _, err := o.GetDnsmasqConfig(ctx, instance); err != nil {
+		return err
+	}
+
+	if err := o.updateSection(ctx, "dhcp", instance, cfg); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the settings of a dnsmasq instance.
+func (c DnsmasqConfig) Validate() error {
+	if c.Domain != "" {
+		if err := validateDNSName("domain", c.Domain); err != nil {
+			return err
+		}
+	}
+
+	if c.Local != "" {
+		if !strings.HasPrefix(c.Local, "/") || !strings.HasSuffix(c.Local, "/") || len(c.Local) < 2 {
+			return fmt.Errorf("invalid local: %s, expected /domain/", c.Local)
+		}
+
+		if domain := strings.Trim(c.Local, "/"); domain != "" {
+			if err := validateDNSName("local", domain); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, domain := range c.RebindDomain {
+		if err := validateDNSName("rebind_domain", strings.Trim(domain, "/")); err != nil {
+			return err
+		}
+	}
+
+	if err := validateUint("cachesize", c.CacheSize, 0, 10000); err != nil {
+		return err
+	}
+
+	bools := [][]string{
+		{"rebind_protection", c.RebindProtection},
+		{"localservice", c.LocalService},
+		{"logqueries", c.LogQueries},
+		{"noresolv", c.NoResolv},
+		{"strictorder", c.StrictOrder},
+	}
+	for _, option := range bools {
+		if err := validateBool(option[0], option[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dnsmasqInstance resolves an empty instance to the section name of the only
+// dnsmasq instance.
+func (o *OpenWRT) dnsmasqInstance(ctx context.Context, instance string) (string, error) {
+	if instance != "" {
+		return instance, nil
+	}
+
+	cfgs, err := o.GetDnsmasqConfigs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(cfgs) {
+	case 0:
+		return "", fmt.Errorf("%w: no dnsmasq instance", ErrSectionNotFound)
+	case 1:
+		for name := range cfgs {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("multiple dnsmasq instances, an instance is required")
+}
@@ -0,0 +1,293 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// GetDnsmasqSets retrieves the dnsmasq firewall sets from the OpenWRT device,
+// keyed by section name.
+func (o *OpenWRT) GetDnsmasqSets(ctx context.Context) (map[string]DnsmasqSet, error) {
+	return getSections[DnsmasqSet](ctx, o, "dhcp", "ipset")
+}
+
+// SetDnsmasqSets adds dnsmasq firewall sets to the OpenWRT device. Nothing
+// is written when a set is invalid or one of its names is already used.
+func (o *OpenWRT) SetDnsmasqSets(ctx context.Context, sets []DnsmasqSet) error {
+	currentSets, err := o.GetDnsmasqSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDnsmasqSets(currentSets, sets); err != nil {
+		return err
+	}
+
+	for _, set := range sets {
+		if _, err := o.addSection(ctx, "dhcp", "ipset", set); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDnsmasqSets updates existing dnsmasq firewall sets on the OpenWRT
+// device, matched by their first name. Empty fields are removed from the set.
+func (o *OpenWRT) UpdateDnsmasqSets(ctx context.Context, updateSets []DnsmasqSet) error {
+	currentSets, err := o.GetDnsmasqSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateSets))
+	var notFound []string
+	for index, set := range updateSets {
+		if err := set.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findDnsmasqSet(currentSets, set.Name[0])
+		if !ok {
+			notFound = append(notFound, set.Name[0])
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dnsmasq sets not found: %v", notFound)
+	}
+
+	for index, set := range updateSets {
+		if err := o.updateSection(ctx, "dhcp", cfgs[index], set); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDnsmasqSets deletes the dnsmasq sections of firewall sets from the
+// OpenWRT device, matched by any of their names.
+func (o *OpenWRT) DeleteDnsmasqSets(ctx context.Context, names []string) error {
+	currentSets, err := o.GetDnsmasqSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, name := range names {
+		cfg, ok := findDnsmasqSet(currentSets, name)
+		if !ok {
+			notFound = append(notFound, name)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("dnsmasq sets not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDomainPBRPolicy adds a PBR policy routing the domains through its
+// interface. The dest_addr of the policy points at an IPv4 set, declared in
+// the firewall config so fw4 creates it in inet fw4, which dnsmasq fills
+// with the addresses the domains resolve to. Everything is validated and
+// staged before anything is committed, then the firewall, dhcp and pbr configs
+// are committed in this order. On error the configs not yet committed are
+// reverted, but a failed commit leaves the configs committed before it in
+// place.
+func (o *OpenWRT) SetDomainPBRPolicy(ctx context.Context, policy PBR, domains []string) error {
+	if len(domains) == 0 {
+		return fmt.Errorf("domains are required")
+	}
+
+	name := pbrSetName(policy.Name)
+	policy.DsrAddr = "@" + name
+	if err := o.validatePBRPolicy(ctx, policy); err != nil {
+		return err
+	}
+
+	set := DnsmasqSet{
+		Name:        UciList{name},
+		Domain:      domains,
+		Table:       "fw4",
+		TableFamily: "inet",
+	}
+
+	currentSets, err := o.GetDnsmasqSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDnsmasqSets(currentSets, []DnsmasqSet{set}); err != nil {
+		return err
+	}
+
+	firewallSets, err := getSections[firewallSet](ctx, o, "firewall", "ipset")
+	if err != nil {
+		return err
+	}
+
+	for _, firewallSet := range firewallSets {
+		if firewallSet.Name == name {
+			return fmt.Errorf("%w: firewall set %s already exists", ErrConflict, name)
+		}
+	}
+
+	currentPolicies, err := o.GetPBRPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := findPBRPolicy(currentPolicies, policy.Name); ok {
+		return fmt.Errorf("%w: pbr policy %s already exists", ErrConflict, policy.Name)
+	}
+
+	configs := []string{"firewall", "dhcp", "pbr"}
+	if err := o.stageDomainPBRPolicy(ctx, name, set, policy); err != nil {
+		return o.revertConfigs(ctx, configs, err)
+	}
+
+	for index, config := range configs {
+		if _, err := o.lucirpc.Uci(ctx, "commit", []string{config}); err != nil {
+			return o.revertConfigs(ctx, configs[index:], err)
+		}
+	}
+
+	return nil
+}
+
+// stageDomainPBRPolicy adds the firewall set, the dnsmasq set and the policy
+// of a domain based policy without committing.
+func (o *OpenWRT) stageDomainPBRPolicy(ctx context.Context, name string, set DnsmasqSet, policy PBR) error {
+	if _, err := o.addSection(ctx, "firewall", "ipset", firewallSet{Name: name, Family: "ipv4", Match: UciList{"dest_ip"}}); err != nil {
+		return err
+	}
+
+	if _, err := o.addSection(ctx, "dhcp", "ipset", set); err != nil {
+		return err
+	}
+
+	_, err := o.addSection(ctx, "pbr", "policy", policy)
+	return err
+}
+
+// Validate checks the fields of a dnsmasq firewall set.
+func (s DnsmasqSet) Validate() error {
+	if len(s.Name) == 0 {
+		return fmt.Errorf("name is required")
+	}
+
+	for _, name := range s.Name {
+		if name == "" || strings.ContainsFunc(name, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.')
+		}) {
+			return fmt.Errorf("invalid name: %s", name)
+		}
+	}
+
+	if len(s.Domain) == 0 {
+		return fmt.Errorf("domain is required")
+	}
+
+	for _, domain := range s.Domain {
+		if err := validateDNSName("domain", domain); err != nil {
+			return err
+		}
+	}
+
+	if s.Table != "" {
+		if err := validateUciName("table", s.Table); err != nil {
+			return err
+		}
+	}
+
+	if s.TableFamily != "" && !slices.Contains([]string{"inet", "ip", "ip6"}, s.TableFamily) {
+		return fmt.Errorf("invalid table_family: %s, expected inet, ip or ip6", s.TableFamily)
+	}
+
+	return nil
+}
+
+// checkDnsmasqSets validates the new sets and checks their names are not
+// used by the current sets or by each other.
+func checkDnsmasqSets(currentSets map[string]DnsmasqSet, sets []DnsmasqSet) error {
+	currentSets = maps.Clone(currentSets)
+	for index, set := range sets {
+		if err := set.Validate(); err != nil {
+			return err
+		}
+
+		for _, name := range set.Name {
+			if _, ok := findDnsmasqSet(currentSets, name); ok {
+				return fmt.Errorf("%w: dnsmasq set %s already exists", ErrConflict, name)
+			}
+		}
+		currentSets[fmt.Sprintf("new set %d", index)] = set
+	}
+
+	return nil
+}
+
+// firewallSet is a set declared in the firewall config (ipset section),
+// which fw4 creates in its inet fw4 table.
+type firewallSet struct {
+	Type   string  `json:".type"`
+	Name   string  `json:"name,omitempty"`
+	Family string  `json:"family,omitempty"`
+	Match  UciList `json:"match,omitempty"`
+}
+
+// pbrSetName returns the name of the set backing the domains of a policy.
+func pbrSetName(policy string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(policy))
+
+	return "pbr_" + name + "_dst"
+}
+
+func findDnsmasqSet(sets map[string]DnsmasqSet, name string) (string, bool) {
+	for cfg, set := range sets {
+		if slices.Contains(set.Name, name) {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
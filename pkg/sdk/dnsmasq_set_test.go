@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Dnsmasq sets", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "wg0", map[string]any{"proto": "wireguard"})
+		router.AddSection("dhcp", "ipset", "", map[string]any{
+			"name":   []any{"streaming"},
+			"domain": []any{"netflix.com", "nflxvideo.net"},
+			"table":  "fw4",
+		})
+	})
+
+	It("create, update and delete a set", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.GetDnsmasqSets(ctx)).To(Equal(map[string]DnsmasqSet{
+			"cfg1": {Type: "ipset", Name: UciList{"streaming"}, Domain: UciList{"netflix.com", "nflxvideo.net"}, Table: "fw4"},
+		}))
+
+		set := DnsmasqSet{Name: UciList{"ads", "ads6"}, Domain: UciList{"doubleclick.net"}, Table: "fw4", TableFamily: "inet"}
+		Expect(o.SetDnsmasqSets(ctx, []DnsmasqSet{set})).To(Succeed())
+		Expect(router.Sections("dhcp", "ipset")).To(ContainElement(And(
+			HaveKeyWithValue("name", []any{"ads", "ads6"}),
+			HaveKeyWithValue("table_family", "inet"),
+		)))
+
+		err := o.SetDnsmasqSets(ctx, []DnsmasqSet{{Name: UciList{"ads6"}, Domain: UciList{"example.com"}}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		set.Domain = UciList{"doubleclick.net", "googlesyndication.com"}
+		set.TableFamily = ""
+		Expect(o.UpdateDnsmasqSets(ctx, []DnsmasqSet{set})).To(Succeed())
+		Expect(router.Sections("dhcp", "ipset")).To(ContainElement(And(
+			HaveKeyWithValue("domain", []any{"doubleclick.net", "googlesyndication.com"}),
+			Not(HaveKey("table_family")),
+		)))
+
+		Expect(o.DeleteDnsmasqSets(ctx, []string{"ads6", "streaming"})).To(Succeed())
+		Expect(router.Sections("dhcp", "ipset")).To(BeEmpty())
+		Expect(o.DeleteDnsmasqSets(ctx, []string{"ads"})).To(MatchError("dnsmasq sets not found: [ads]"))
+		Expect(router.Commits("dhcp")).To(Equal(3))
+	})
+
+	It("create a domain based policy with its set", func() {
+		o := newFakeOpenWRT(router)
+		policy := PBR{Name: "Work VPN", Interface: "wg0"}
+		Expect(o.SetDomainPBRPolicy(ctx, policy, []string{"corp.example.com", "jira.example.com"})).To(Succeed())
+		Expect(router.Sections("dhcp", "ipset")).To(ContainElement(And(
+			HaveKeyWithValue("name", []any{"pbr_work_vpn_dst"}),
+			HaveKeyWithValue("domain", []any{"corp.example.com", "jira.example.com"}),
+			HaveKeyWithValue("table", "fw4"),
+			HaveKeyWithValue("table_family", "inet"),
+		)))
+		Expect(router.Sections("firewall", "ipset")).To(ConsistOf(And(
+			HaveKeyWithValue("name", "pbr_work_vpn_dst"),
+			HaveKeyWithValue("family", "ipv4"),
+			HaveKeyWithValue("match", []any{"dest_ip"}),
+		)))
+		Expect(router.Sections("pbr", "policy")).To(ConsistOf(And(
+			HaveKeyWithValue("name", "Work VPN"),
+			HaveKeyWithValue("dest_addr", "@pbr_work_vpn_dst"),
+			HaveKeyWithValue("interface", "wg0"),
+		)))
+		Expect(router.Commits("firewall")).To(Equal(1))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		err := o.SetDomainPBRPolicy(ctx, policy, []string{"corp.example.com"})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(router.Sections("pbr", "policy")).To(HaveLen(1))
+	})
+
+	It("read the other dhcp sections next to a set", func() {
+		router.AddSection("dhcp", "dnsmasq", "", map[string]any{"domain": "lan"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "nas.lan", "ip": "192.168.1.10"})
+		router.AddSection("dhcp", "host", "", map[string]any{"name": "nas", "mac": "00:11:22:33:44:55", "ip": "192.168.1.10"})
+		o := newFakeOpenWRT(router)
+		Expect(o.GetDNSRecords(ctx)).To(HaveLen(1))
+		Expect(o.GetDnsmasqConfigs(ctx)).To(Equal(map[string]DnsmasqConfig{
+			"cfg2": {Type: "dnsmasq", Domain: "lan"},
+		}))
+		Expect(o.GetHosts(ctx)).To(HaveKey("nas"))
+	})
+
+	It("revert the configs not committed when a commit fails", func() {
+		router.FailCommit("pbr")
+		o := newFakeOpenWRT(router)
+		err := o.SetDomainPBRPolicy(ctx, PBR{Name: "work", Interface: "wg0"}, []string{"corp.example.com"})
+		Expect(err).To(MatchError(ContainSubstring("commit of pbr failed")))
+		Expect(router.Commits("firewall")).To(Equal(1))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+		Expect(router.Sections("dhcp", "ipset")).To(HaveLen(2))
+		Expect(router.Sections("pbr", "policy")).To(BeEmpty())
+	})
+
+	It("keep a firewall set named like the policy set", func() {
+		router.AddSection("firewall", "ipset", "", map[string]any{"name": "pbr_work_dst", "family": "ipv4"})
+		o := newFakeOpenWRT(router)
+		err := o.SetDomainPBRPolicy(ctx, PBR{Name: "work", Interface: "wg0"}, []string{"corp.example.com"})
+		Expect(err).To(MatchError(ContainSubstring("firewall set pbr_work_dst already exists")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(router.Sections("dhcp", "ipset")).To(HaveLen(1))
+		Expect(router.Sections("firewall", "ipset")).To(HaveLen(1))
+		Expect(router.Sections("pbr", "policy")).To(BeEmpty())
+	})
+
+	It("write nothing when the policy is invalid", func() {
+		o := newFakeOpenWRT(router)
+		err := o.SetDomainPBRPolicy(ctx, PBR{Name: "work", Interface: "wg1"}, []string{"corp.example.com"})
+		Expect(err).To(MatchError("invalid interface: wg1, not a network interface"))
+		Expect(router.Sections("dhcp", "ipset")).To(HaveLen(1))
+		Expect(router.Commits("dhcp")).To(Equal(0))
+	})
+
+	It("validate", func() {
+		Expect(DnsmasqSet{Domain: UciList{"example.com"}}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqSet{Name: UciList{"a b"}, Domain: UciList{"example.com"}}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqSet{Name: UciList{"ads"}}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqSet{Name: UciList{"ads"}, Domain: UciList{"example.com"}, TableFamily: "arp"}.Validate()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Dnsmasq", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("dhcp", "dnsmasq", "", map[string]any{
+			"domain":        "lan",
+			"local":         "/lan/",
+			"cachesize":     "1000",
+			"rebind_domain": "plex.direct",
+		})
+	})
+
+	It("get the only instance", func() {
+		o := newFakeOpenWRT(router)
+		cfg, err := o.GetDnsmasqConfig(ctx, "")
+		Expect(err).To(BeNil())
+		Expect(cfg).To(Equal(DnsmasqConfig{
+			Type:         "dnsmasq",
+			Domain:       "lan",
+			Local:        "/lan/",
+			CacheSize:    "1000",
+			RebindDomain: UciList{"plex.direct"},
+		}))
+	})
+
+	It("update an instance", func() {
+		o := newFakeOpenWRT(router)
+		cfg, err := o.GetDnsmasqConfig(ctx, "")
+		Expect(err).To(BeNil())
+
+		cfg.CacheSize = ""
+		cfg.LogQueries = "1"
+		cfg.RebindDomain = append(cfg.RebindDomain, "home.example.com")
+		Expect(o.UpdateDnsmasqConfig(ctx, "", cfg)).To(Succeed())
+
+		sections := router.Sections("dhcp", "dnsmasq")
+		Expect(sections).To(HaveLen(1))
+		Expect(sections[0]).ToNot(HaveKey("cachesize"))
+		Expect(sections[0]).To(HaveKeyWithValue("logqueries", "1"))
+		Expect(sections[0]).To(HaveKeyWithValue("rebind_domain", []any{"plex.direct", "home.example.com"}))
+		Expect(router.Commits("dhcp")).To(Equal(1))
+	})
+
+	It("require an instance when running several", func() {
+		router.AddSection("dhcp", "dnsmasq", "guest", map[string]any{"domain": "guest"})
+		o := newFakeOpenWRT(router)
+
+		_, err := o.GetDnsmasqConfig(ctx, "")
+		Expect(err).ToNot(BeNil())
+
+		cfg, err := o.GetDnsmasqConfig(ctx, "guest")
+		Expect(err).To(BeNil())
+		Expect(cfg.Domain).To(Equal("guest"))
+	})
+
+	It("not found", func() {
+		o := newFakeOpenWRT(router)
+		_, err := o.GetDnsmasqConfig(ctx, "whatever")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+	})
+
+	It("validate", func() {
+		Expect(DnsmasqConfig{Local: "lan"}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqConfig{CacheSize: "-1"}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqConfig{LogQueries: "yes"}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqConfig{Domain: "bad_domain-.lan"}.Validate()).ToNot(Succeed())
+		Expect(DnsmasqConfig{Domain: "lan", Local: "/lan/", CacheSize: "150", StrictOrder: "1"}.Validate()).To(Succeed())
+	})
+})
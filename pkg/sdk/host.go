@@ -0,0 +1,280 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// GetHosts retrieves every host from the OpenWRT device, keyed by name,
+// assembling each static lease with the DNS records of its name.
+func (o *OpenWRT) GetHosts(ctx context.Context) (map[string]Host, error) {
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make(map[string]Host)
+	for _, lease := range leases {
+		if lease.Name == "" {
+			continue
+		}
+		hosts[lease.Name] = assembleHost(lease, records)
+	}
+
+	return hosts, nil
+}
+
+// GetHost retrieves a host by name from the OpenWRT device.
+func (o *OpenWRT) GetHost(ctx context.Context, name string) (Host, error) {
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return Host{}, err
+	}
+
+	cfg, ok := findStaticLease(leases, name)
+	if !ok {
+		return Host{}, fmt.Errorf("%w: host %s", ErrSectionNotFound, name)
+	}
+
+	return assembleHost(leases[cfg], records), nil
+}
+
+// CreateHost adds the static lease, DNS records and aliases of a host to the
+// OpenWRT device in a single commit.
+func (o *OpenWRT) CreateHost(ctx context.Context, host Host) error {
+	if err := host.Validate(); err != nil {
+		return err
+	}
+
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := hostConflicts(leases, records, host, ""); err != nil {
+		return err
+	}
+
+	if _, err := o.addSection(ctx, "dhcp", "host", host.lease()); err != nil {
+		return err
+	}
+
+	if err := o.addHostRecords(ctx, host); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateHost replaces the static lease, DNS records and aliases of an
+// existing host on the OpenWRT device in a single commit.
+func (o *OpenWRT) UpdateHost(ctx context.Context, host Host) error {
+	if err := host.Validate(); err != nil {
+		return err
+	}
+
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, ok := findStaticLease(leases, host.Name)
+	if !ok {
+		return fmt.Errorf("%w: host %s", ErrSectionNotFound, host.Name)
+	}
+
+	stale := hostRecords(records, host.Name)
+	for _, recordCfg := range stale {
+		delete(records, recordCfg)
+	}
+
+	if err := hostConflicts(leases, records, host, cfg); err != nil {
+		return err
+	}
+
+	lease := leases[cfg]
+	lease.MAC = host.MAC
+	lease.IP = host.IPv4
+	if err := o.updateSection(ctx, "dhcp", cfg, lease); err != nil {
+		return err
+	}
+
+	for _, recordCfg := range stale {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", recordCfg}); err != nil {
+			return err
+		}
+	}
+
+	if err := o.addHostRecords(ctx, host); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteHost deletes the static lease, DNS records and aliases of a host
+// from the OpenWRT device in a single commit.
+func (o *OpenWRT) DeleteHost(ctx context.Context, name string) error {
+	leases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, ok := findStaticLease(leases, name)
+	if !ok {
+		return fmt.Errorf("%w: host %s", ErrSectionNotFound, name)
+	}
+
+	for _, section := range append([]string{cfg}, hostRecords(records, name)...) {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", section}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a host.
+func (h Host) Validate() error {
+	if err := h.lease().Validate(); err != nil {
+		return err
+	}
+
+	for _, ip := range h.IPv6 {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil || !addr.Is6() {
+			return fmt.Errorf("invalid ipv6: %s", ip)
+		}
+	}
+
+	for _, alias := range h.Aliases {
+		if err := validateDNSName("alias", alias); err != nil {
+			return err
+		}
+
+		if sameDNSName(alias, h.Name) {
+			return fmt.Errorf("invalid alias: %s, it is the host name", alias)
+		}
+	}
+
+	return nil
+}
+
+func (h Host) lease() StaticLease {
+	return StaticLease{
+		Name: h.Name,
+		MAC:  h.MAC,
+		IP:   h.IPv4,
+	}
+}
+
+func (o *OpenWRT) addHostRecords(ctx context.Context, host Host) error {
+	if host.IPv4 != "" {
+		if err := o.addA(ctx, DNSRecord{Type: "A", Name: host.Name, IP: host.IPv4}); err != nil {
+			return err
+		}
+	}
+
+	for _, ip := range host.IPv6 {
+		if err := o.addA(ctx, DNSRecord{Type: "AAAA", Name: host.Name, IP: ip}); err != nil {
+			return err
+		}
+	}
+
+	for _, alias := range host.Aliases {
+		if err := o.addCName(ctx, DNSRecord{Type: "CNAME", CName: alias, Target: host.Name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assembleHost returns the host of a lease with its records.
+func assembleHost(lease StaticLease, records map[string]DNSRecord) Host {
+	host := Host{
+		Name: lease.Name,
+		MAC:  lease.MAC,
+		IPv4: lease.IP,
+	}
+
+	for _, record := range sortedDNSRecords(records) {
+		switch {
+		case record.Type == "A" && host.IPv4 == "" && sameDNSName(record.Name, lease.Name):
+			host.IPv4 = record.IP
+		case record.Type == "AAAA" && sameDNSName(record.Name, lease.Name):
+			host.IPv6 = append(host.IPv6, record.IP)
+		case record.Type == "CNAME" && sameDNSName(record.Target, lease.Name):
+			host.Aliases = append(host.Aliases, record.CName)
+		}
+	}
+
+	return host
+}
+
+// hostRecords returns the sorted sections of the A, AAAA and CNAME records of a host.
+func hostRecords(records map[string]DNSRecord, name string) []string {
+	var cfgs []string
+	for cfg, record := range records {
+		switch record.Type {
+		case "A", "AAAA":
+			if sameDNSName(record.Name, name) {
+				cfgs = append(cfgs, cfg)
+			}
+		case "CNAME":
+			if sameDNSName(record.Target, name) {
+				cfgs = append(cfgs, cfg)
+			}
+		}
+	}
+	slices.Sort(cfgs)
+
+	return cfgs
+}
+
+// hostConflicts checks the lease of the host, that its name has no records
+// yet and that its aliases are not used by other records.
+func hostConflicts(leases map[string]StaticLease, records map[string]DNSRecord, host Host, ignore string) error {
+	conflicts := StaticLeaseConflicts(leases, records, host.lease(), ignore)
+	for _, record := range sortedDNSRecords(records) {
+		name := record.recordName()
+		switch {
+		case record.Type == "CNAME" && sameDNSName(name, host.Name):
+			conflicts = append(conflicts, fmt.Sprintf("name is an alias of %s", record.Target))
+		case record.Type == "CNAME" && sameDNSName(record.Target, host.Name):
+			conflicts = append(conflicts, fmt.Sprintf("dns record %s is already an alias", name))
+		case record.Type == "AAAA" && sameDNSName(name, host.Name),
+			record.Type == "A" && sameDNSName(name, host.Name) && record.IP == host.IPv4:
+			conflicts = append(conflicts, fmt.Sprintf("dns record %s %s already exists", name, record.IP))
+		}
+
+		if slices.ContainsFunc(host.Aliases, func(alias string) bool { return sameDNSName(alias, name) }) {
+			conflicts = append(conflicts, fmt.Sprintf("alias %s is already a %s record", name, record.Type))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: host %s: %s", ErrConflict, host.Name, strings.Join(conflicts, ", "))
+	}
+
+	return nil
+}
+
+func sameDNSName(a, b string) bool {
+	return normalizeDNSName(a) == normalizeDNSName(b)
+}
@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Hosts", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("dhcp", "host", "nas", map[string]any{
+			"name": "nas",
+			"mac":  "aa:bb:cc:dd:ee:01",
+			"ip":   "192.168.1.10",
+		})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "nas", "ip": "192.168.1.10"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "nas", "ip": "fd00::10"})
+		router.AddSection("dhcp", "cname", "", map[string]any{"cname": "files", "target": "nas"})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "printer", "ip": "192.168.1.30"})
+	})
+
+	It("get hosts", func() {
+		o := newFakeOpenWRT(router)
+		hosts, err := o.GetHosts(ctx)
+		Expect(err).To(BeNil())
+		Expect(hosts).To(Equal(map[string]Host{
+			"nas": {
+				Name:    "nas",
+				MAC:     []string{"aa:bb:cc:dd:ee:01"},
+				IPv4:    "192.168.1.10",
+				IPv6:    []string{"fd00::10"},
+				Aliases: []string{"files"},
+			},
+		}))
+
+		_, err = o.GetHost(ctx, "tv")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+	})
+
+	It("create, update and delete a host in one commit each", func() {
+		o := newFakeOpenWRT(router)
+		host := Host{
+			Name:    "tv",
+			MAC:     []string{"aa:bb:cc:dd:ee:03"},
+			IPv4:    "192.168.1.20",
+			IPv6:    []string{"fd00::20"},
+			Aliases: []string{"media"},
+		}
+		Expect(o.CreateHost(ctx, host)).To(Succeed())
+		Expect(router.Commits("dhcp")).To(Equal(1))
+		Expect(o.GetHost(ctx, "tv")).To(Equal(host))
+
+		host.IPv4 = "192.168.1.21"
+		host.IPv6 = nil
+		host.Aliases = []string{"living-room", "media"}
+		Expect(o.UpdateHost(ctx, host)).To(Succeed())
+		Expect(router.Commits("dhcp")).To(Equal(2))
+		Expect(o.GetHost(ctx, "tv")).To(Equal(host))
+		Expect(router.Sections("dhcp", "domain")).To(HaveLen(4))
+
+		Expect(o.DeleteHost(ctx, "tv")).To(Succeed())
+		Expect(router.Commits("dhcp")).To(Equal(3))
+		Expect(router.Sections("dhcp", "host")).To(HaveLen(1))
+		Expect(router.Sections("dhcp", "domain")).To(HaveLen(3))
+		Expect(router.Sections("dhcp", "cname")).To(HaveLen(1))
+	})
+
+	It("detect conflicts", func() {
+		o := newFakeOpenWRT(router)
+		err := o.CreateHost(ctx, Host{Name: "tv", MAC: []string{"aa:bb:cc:dd:ee:03"}, Aliases: []string{"printer"}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(err.Error()).To(Equal("conflict: host tv: alias printer is already a A record"))
+
+		err = o.CreateHost(ctx, Host{Name: "files", MAC: []string{"aa:bb:cc:dd:ee:03"}})
+		Expect(err).To(MatchError("conflict: host files: name is an alias of nas"))
+		Expect(router.Commits("dhcp")).To(Equal(0))
+	})
+
+	It("validate", func() {
+		Expect(Host{Name: "tv", IPv6: []string{"192.168.1.1"}}.Validate()).ToNot(Succeed())
+		Expect(Host{Name: "tv", Aliases: []string{"tv"}}.Validate()).ToNot(Succeed())
+		Expect(Host{Name: "tv", MAC: []string{"aa:bb:cc:dd:ee:03"}, IPv6: []string{"fd00::1"}}.Validate()).To(Succeed())
+	})
+})
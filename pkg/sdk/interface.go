@@ -0,0 +1,206 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const networkReloadCommand = "ubus call network reload"
+
+var interfaceProtos = []string{"static", "dhcp", "dhcpv6", "pppoe", "none", "wireguard"}
+
+// GetInterfaces retrieves the network interfaces from the OpenWRT device,
+// keyed by name.
+func (o *OpenWRT) GetInterfaces(ctx context.Context) (map[string]Interface, error) {
+	return getSections[Interface](ctx, o, "network", "interface")
+}
+
+// GetInterface retrieves a network interface by name from the OpenWRT device.
+func (o *OpenWRT) GetInterface(ctx context.Context, name string) (Interface, error) {
+	var iface Interface
+	if err := o.getSection(ctx, "network", name, &iface); err != nil {
+		return Interface{}, err
+	}
+
+	if iface.Type != "interface" {
+		return Interface{}, fmt.Errorf("%w: network.%s is not an interface", ErrSectionNotFound, name)
+	}
+
+	return iface, nil
+}
+
+// SetInterfaces adds network interfaces to the OpenWRT device, commits and
+// reloads the network so they come up. Nothing is written when an interface
+// is invalid or its name is already used by a section of the network config.
+func (o *OpenWRT) SetInterfaces(ctx context.Context, ifaces []Interface) error {
+	kinds, err := o.sectionKinds(ctx, "network")
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range ifaces {
+		if err := iface.Validate(); err != nil {
+			return err
+		}
+
+		if err := checkSectionName(kinds, "network", iface.Name); err != nil {
+			return err
+		}
+		kinds[iface.Name] = "interface"
+	}
+
+	for _, iface := range ifaces {
+		if err := o.addNamedSection(ctx, "network", "interface", iface.Name, iface); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateInterfaces updates existing network interfaces on the OpenWRT
+// device, matched by name, then commits and reloads the network. Empty
+// fields are removed from the interface.
+func (o *OpenWRT) UpdateInterfaces(ctx context.Context, updateIfaces []Interface) error {
+	currentIfaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound []string
+	for _, iface := range updateIfaces {
+		if err := iface.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := currentIfaces[iface.Name]; !ok {
+			notFound = append(notFound, iface.Name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("interfaces not found: %v", notFound)
+	}
+
+	for _, iface := range updateIfaces {
+		if err := o.updateSection(ctx, "network", iface.Name, iface); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteInterfaces deletes network interfaces from the OpenWRT device, then
+// commits and reloads the network, bringing them down.
+func (o *OpenWRT) DeleteInterfaces(ctx context.Context, names []string) error {
+	currentIfaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound []string
+	for _, name := range names {
+		if _, ok := currentIfaces[name]; !ok {
+			notFound = append(notFound, name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("interfaces not found: %v", notFound)
+	}
+
+	for _, name := range names {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", name}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// Validate checks the fields of a network interface.
+func (i Interface) Validate() error {
+	if err := validateUciName("name", i.Name); err != nil {
+		return err
+	}
+
+	if !slices.Contains(interfaceProtos, i.Proto) {
+		return fmt.Errorf("invalid proto: %s, expected %s", i.Proto, strings.Join(interfaceProtos, ", "))
+	}
+
+	if strings.ContainsFunc(i.Device, unicode.IsSpace) {
+		return fmt.Errorf("invalid device: %s", i.Device)
+	}
+
+	if i.Proto == "static" {
+		if len(i.IPAddr) == 0 && len(i.IP6Addr) == 0 {
+			return fmt.Errorf("ipaddr or ip6addr is required for static interfaces")
+		}
+	} else if len(i.IPAddr) > 0 || len(i.IP6Addr) > 0 || i.Netmask != "" || i.Gateway != "" {
+		return fmt.Errorf("ipaddr, ip6addr, netmask and gateway are only set on static interfaces")
+	}
+
+	for _, ipaddr := range i.IPAddr {
+		if strings.Contains(ipaddr, "/") && i.Netmask != "" {
+			return fmt.Errorf("invalid ipaddr: %s, netmask is set", ipaddr)
+		}
+
+		if _, err := parseIPv4Prefix(ipaddr, i.Netmask); err != nil {
+			return err
+		}
+	}
+
+	for _, ip6addr := range i.IP6Addr {
+		prefix, err := netip.ParsePrefix(ip6addr)
+		if err != nil || !prefix.Addr().Is6() {
+			return fmt.Errorf("invalid ip6addr: %s, expected an IPv6 subnet", ip6addr)
+		}
+	}
+
+	if i.Gateway != "" {
+		if err := validateIPv4("gateway", i.Gateway); err != nil {
+			return err
+		}
+	}
+
+	for _, dns := range i.DNS {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return fmt.Errorf("invalid dns: %s", dns)
+		}
+	}
+
+	if err := validateUint("metric", i.Metric, 0, 1<<32-1); err != nil {
+		return err
+	}
+
+	if err := validateUint("ip6assign", i.IP6Assign, 0, 128); err != nil {
+		return err
+	}
+
+	if i.IP6Hint != "" {
+		if _, err := strconv.ParseUint(i.IP6Hint, 16, 16); err != nil {
+			return fmt.Errorf("invalid ip6hint: %s, expected a hexadecimal subnet id", i.IP6Hint)
+		}
+	}
+
+	if i.Proto == "pppoe" && i.Username == "" {
+		return fmt.Errorf("username is required for pppoe interfaces")
+	}
+
+	return nil
+}
+
+// commitNetwork commits the network config and reloads the interfaces.
+func (o *OpenWRT) commitNetwork(ctx context.Context) error {
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"network"}); err != nil {
+		return err
+	}
+
+	return o.ReloadNetwork(ctx)
+}
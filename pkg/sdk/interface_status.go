@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const interfaceDumpCommand = "ubus call network.interface dump"
+
+// GetInterfaceStatuses retrieves the runtime state of every logical network
+// interface from netifd, keyed by name.
+func (o *OpenWRT) GetInterfaceStatuses(ctx context.Context) (map[string]InterfaceStatus, error) {
+	output, err := o.exec(ctx, interfaceDumpCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInterfaceDump(output)
+}
+
+// GetInterfaceStatus retrieves the runtime state of a logical network
+// interface from netifd.
+func (o *OpenWRT) GetInterfaceStatus(ctx context.Context, name string) (InterfaceStatus, error) {
+	statuses, err := o.GetInterfaceStatuses(ctx)
+	if err != nil {
+		return InterfaceStatus{}, err
+	}
+
+	status, ok := statuses[name]
+	if !ok {
+		return InterfaceStatus{}, fmt.Errorf("%w: interface %s", ErrSectionNotFound, name)
+	}
+
+	return status, nil
+}
+
+// IfUp brings a logical network interface up, restarting it when it is up.
+func (o *OpenWRT) IfUp(ctx context.Context, name string) error {
+	return o.ifaceCommand(ctx, "ifup", name)
+}
+
+// IfDown brings a logical network interface down.
+func (o *OpenWRT) IfDown(ctx context.Context, name string) error {
+	return o.ifaceCommand(ctx, "ifdown", name)
+}
+
+// ReloadNetwork applies the committed network config, restarting only the
+// interfaces whose config changed.
+func (o *OpenWRT) ReloadNetwork(ctx context.Context) error {
+	_, err := o.exec(ctx, networkReloadCommand)
+	return err
+}
+
+func (o *OpenWRT) ifaceCommand(ctx context.Context, command, name string) error {
+	// the name ends up in a shell command
+	if err := validateUciName("interface", name); err != nil {
+		return err
+	}
+
+	_, err := o.exec(ctx, command+" "+name)
+	return err
+}
+
+type netifdAddress struct {
+	Address string `json:"address"`
+	Mask    int    `json:"mask"`
+}
+
+func (a netifdAddress) String() string {
+	return a.Address + "/" + strconv.Itoa(a.Mask)
+}
+
+// parseInterfaceDump parses the output of the network.interface dump ubus call.
+func parseInterfaceDump(output string) (map[string]InterfaceStatus, error) {
+	var dump struct {
+		Interface []struct {
+			Interface   string          `json:"interface"`
+			Up          bool            `json:"up"`
+			Pending     bool            `json:"pending"`
+			Available   bool            `json:"available"`
+			Uptime      int64           `json:"uptime"`
+			Proto       string          `json:"proto"`
+			Device      string          `json:"device"`
+			L3Device    string          `json:"l3_device"`
+			IPv4Address []netifdAddress `json:"ipv4-address"`
+			IPv6Address []netifdAddress `json:"ipv6-address"`
+			IPv6Prefix  []netifdAddress `json:"ipv6-prefix"`
+			Route       []struct {
+				Target  string `json:"target"`
+				Mask    int    `json:"mask"`
+				Nexthop string `json:"nexthop"`
+				Metric  int    `json:"metric"`
+			} `json:"route"`
+			DNSServer []string `json:"dns-server"`
+		} `json:"interface"`
+	}
+	if err := json.Unmarshal([]byte(output), &dump); err != nil || dump.Interface == nil {
+		return nil, fmt.Errorf("unexpected network.interface dump output: %q", output)
+	}
+
+	statuses := make(map[string]InterfaceStatus, len(dump.Interface))
+	for _, iface := range dump.Interface {
+		status := InterfaceStatus{
+			Name:      iface.Interface,
+			Up:        iface.Up,
+			Pending:   iface.Pending,
+			Available: iface.Available,
+			Uptime:    time.Duration(iface.Uptime) * time.Second,
+			Proto:     iface.Proto,
+			Device:    iface.Device,
+			L3Device:  iface.L3Device,
+			DNS:       iface.DNSServer,
+		}
+
+		for _, addr := range iface.IPv4Address {
+			status.IPv4 = append(status.IPv4, addr.String())
+		}
+
+		for _, addr := range iface.IPv6Address {
+			status.IPv6 = append(status.IPv6, addr.String())
+		}
+
+		for _, prefix := range iface.IPv6Prefix {
+			status.DelegatedPrefixes = append(status.DelegatedPrefixes, prefix.String())
+		}
+
+		for _, route := range iface.Route {
+			status.Routes = append(status.Routes, InterfaceRoute{
+				Target:  netifdAddress{Address: route.Target, Mask: route.Mask}.String(),
+				Nexthop: route.Nexthop,
+				Metric:  route.Metric,
+			})
+		}
+
+		statuses[iface.Interface] = status
+	}
+
+	return statuses, nil
+}
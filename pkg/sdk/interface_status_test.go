@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+const interfaceDump = `{
+	"interface": [
+		{
+			"interface": "lan", "up": true, "pending": false, "available": true, "uptime": 3600,
+			"l3_device": "br-lan", "proto": "static", "device": "br-lan",
+			"ipv4-address": [{"address": "192.168.1.1", "mask": 24}],
+			"ipv6-address": [],
+			"route": [], "dns-server": []
+		},
+		{
+			"interface": "wan", "up": true, "pending": false, "available": true, "uptime": 120,
+			"l3_device": "pppoe-wan", "proto": "pppoe", "device": "eth1",
+			"ipv4-address": [{"address": "203.0.113.7", "mask": 32}],
+			"ipv6-address": [{"address": "2001:db8::7", "mask": 64}],
+			"ipv6-prefix": [{"address": "2001:db8:100::", "mask": 56}],
+			"route": [{"target": "0.0.0.0", "mask": 0, "nexthop": "203.0.113.1", "metric": 10, "source": "203.0.113.7/32"}],
+			"dns-server": ["203.0.113.53"]
+		},
+		{"interface": "guest", "up": false, "pending": false, "available": false, "proto": "static", "device": "br-guest"}
+	]
+}`
+
+var _ = Describe("Interface status", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.SetOutput(interfaceDumpCommand, interfaceDump)
+	})
+
+	It("get the status of the interfaces", func() {
+		o := newFakeOpenWRT(router)
+		statuses, err := o.GetInterfaceStatuses(ctx)
+		Expect(err).To(BeNil())
+		Expect(statuses).To(HaveLen(3))
+		Expect(statuses["lan"].IPv4).To(Equal([]string{"192.168.1.1/24"}))
+		Expect(statuses["guest"].Up).To(BeFalse())
+
+		Expect(o.GetInterfaceStatus(ctx, "wan")).To(Equal(InterfaceStatus{
+			Name:      "wan",
+			Up:        true,
+			Available: true,
+			Uptime:    2 * time.Minute,
+			Proto:     "pppoe",
+			Device:    "eth1",
+			L3Device:  "pppoe-wan",
+			IPv4:      []string{"203.0.113.7/32"},
+			IPv6:      []string{"2001:db8::7/64"},
+			Routes: []InterfaceRoute{
+				{Target: "0.0.0.0/0", Nexthop: "203.0.113.1", Metric: 10},
+			},
+			DNS:               []string{"203.0.113.53"},
+			DelegatedPrefixes: []string{"2001:db8:100::/56"},
+		}))
+
+		_, err = o.GetInterfaceStatus(ctx, "wg0")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+	})
+
+	It("fail on unexpected output", func() {
+		router.SetOutput(interfaceDumpCommand, "Command failed: Not found")
+		o := newFakeOpenWRT(router)
+		_, err := o.GetInterfaceStatuses(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("bring interfaces up and down", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.IfDown(ctx, "wan")).To(Succeed())
+		Expect(o.IfUp(ctx, "wan")).To(Succeed())
+		Expect(o.ReloadNetwork(ctx)).To(Succeed())
+		Expect(o.IfUp(ctx, "wan; reboot")).ToNot(Succeed())
+		Expect(router.History()).To(Equal([]string{"ifdown wan", "ifup wan", networkReloadCommand}))
+	})
+})
@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Interfaces", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "device", "", map[string]any{"name": "br-lan", "type": "bridge"})
+		router.AddSection("network", "interface", "lan", map[string]any{
+			"proto":     "static",
+			"device":    "br-lan",
+			"ipaddr":    "192.168.1.1",
+			"netmask":   "255.255.255.0",
+			"ip6assign": "60",
+		})
+		router.AddSection("network", "interface", "wan", map[string]any{"proto": "dhcp", "device": "eth1"})
+	})
+
+	It("get interfaces", func() {
+		o := newFakeOpenWRT(router)
+		ifaces, err := o.GetInterfaces(ctx)
+		Expect(err).To(BeNil())
+		Expect(ifaces).To(HaveLen(2))
+		Expect(ifaces["lan"]).To(Equal(Interface{
+			Type:      "interface",
+			Name:      "lan",
+			Proto:     "static",
+			Device:    "br-lan",
+			IPAddr:    UciList{"192.168.1.1"},
+			Netmask:   "255.255.255.0",
+			IP6Assign: "60",
+		}))
+
+		_, err = o.GetInterface(ctx, "cfg1")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		_, err = o.GetInterface(ctx, "guest")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+	})
+
+	It("create, update and delete an interface and reload the network", func() {
+		o := newFakeOpenWRT(router)
+		iface := Interface{
+			Name:    "tenant10",
+			Proto:   "static",
+			Device:  "br-lan.10",
+			IPAddr:  UciList{"10.0.10.1/24"},
+			DNS:     UciList{"10.0.10.1"},
+			IP6Hint: "a",
+		}
+		Expect(o.SetInterfaces(ctx, []Interface{iface})).To(Succeed())
+		Expect(o.GetInterface(ctx, "tenant10")).To(Equal(Interface{
+			Type:    "interface",
+			Name:    "tenant10",
+			Proto:   "static",
+			Device:  "br-lan.10",
+			IPAddr:  UciList{"10.0.10.1/24"},
+			DNS:     UciList{"10.0.10.1"},
+			IP6Hint: "a",
+		}))
+
+		err := o.SetInterfaces(ctx, []Interface{iface})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		iface.Metric = "20"
+		iface.DNS = nil
+		Expect(o.UpdateInterfaces(ctx, []Interface{iface})).To(Succeed())
+		Expect(router.Sections("network", "interface")).To(ContainElement(And(
+			HaveKeyWithValue(".name", "tenant10"),
+			HaveKeyWithValue("metric", "20"),
+			Not(HaveKey("dns")),
+		)))
+
+		Expect(o.DeleteInterfaces(ctx, []string{"tenant10"})).To(Succeed())
+		Expect(router.Sections("network", "interface")).To(HaveLen(2))
+		Expect(o.DeleteInterfaces(ctx, []string{"tenant10"})).To(MatchError("interfaces not found: [tenant10]"))
+
+		Expect(router.Commits("network")).To(Equal(3))
+		Expect(router.History()).To(Equal([]string{networkReloadCommand, networkReloadCommand, networkReloadCommand}))
+	})
+
+	It("keep a section of another type named like a new interface", func() {
+		router.AddSection("network", "globals", "globals", map[string]any{"ula_prefix": "fd00::/48"})
+		o := newFakeOpenWRT(router)
+		err := o.SetInterfaces(ctx, []Interface{{Name: "globals", Proto: "none"}})
+		Expect(err).To(MatchError(ContainSubstring("network.globals already exists as a globals section")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(router.Sections("network", "globals")).To(HaveLen(1))
+		Expect(router.Commits("network")).To(Equal(0))
+	})
+
+	It("validate", func() {
+		valid := []Interface{
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1"}, Netmask: "255.255.255.0", Gateway: "192.168.1.254"},
+			{Name: "lan6", Proto: "static", IP6Addr: UciList{"fd00::1/64"}},
+			{Name: "wan", Proto: "dhcp", Device: "eth1", Metric: "10"},
+			{Name: "wan", Proto: "pppoe", Device: "eth1", Username: "user", Password: "secret"},
+			{Name: "wg0", Proto: "wireguard"},
+		}
+		for _, iface := range valid {
+			Expect(iface.Validate()).To(Succeed(), "%+v", iface)
+		}
+
+		invalid := []Interface{
+			{Name: "lan-1", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}},
+			{Name: "lan", Proto: "bridge"},
+			{Name: "lan", Proto: "static"},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}, Netmask: "255.255.255.0"},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1"}, Netmask: "255.0.255.0"},
+			{Name: "lan", Proto: "static", IP6Addr: UciList{"192.168.1.1/24"}},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}, Gateway: "fd00::1"},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}, DNS: UciList{"dns.example.com"}},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}, IP6Assign: "129"},
+			{Name: "lan", Proto: "static", IPAddr: UciList{"192.168.1.1/24"}, IP6Hint: "xyz"},
+			{Name: "wan", Proto: "dhcp", IPAddr: UciList{"192.168.1.1/24"}},
+			{Name: "wan", Proto: "dhcp", Device: "eth 1"},
+			{Name: "wan", Proto: "pppoe"},
+		}
+		for _, iface := range invalid {
+			Expect(iface.Validate()).ToNot(Succeed(), "%+v", iface)
+		}
+	})
+})
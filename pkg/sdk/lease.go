@@ -0,0 +1,260 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GetStaticLeases retrieves all static DHCP leases from the OpenWRT device.
+func (o *OpenWRT) GetStaticLeases(ctx context.Context) (map[string]StaticLease, error) {
+	leases, err := getSections[StaticLease](ctx, o, "dhcp", "host")
+	if err != nil {
+		return nil, err
+	}
+
+	// a mac option may hold several addresses separated by spaces
+	for cfg, lease := range leases {
+		var macs UciList
+		for _, mac := range lease.MAC {
+			macs = append(macs, strings.Fields(mac)...)
+		}
+		lease.MAC = macs
+		leases[cfg] = lease
+	}
+
+	return leases, nil
+}
+
+// SetStaticLeases adds new static DHCP leases to the OpenWRT device.
+// Nothing is written when a lease is invalid or conflicts with an existing
+// lease or DNS record.
+func (o *OpenWRT) SetStaticLeases(ctx context.Context, leases []StaticLease) error {
+	currentLeases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, lease := range leases {
+		if err := lease.Validate(); err != nil {
+			return err
+		}
+
+		if conflicts := StaticLeaseConflicts(currentLeases, records, lease, ""); len(conflicts) > 0 {
+			return fmt.Errorf("%w: lease %s: %s", ErrConflict, lease.Name, strings.Join(conflicts, ", "))
+		}
+
+		// leases of the same call must not conflict with each other either
+		currentLeases[fmt.Sprintf("new lease %s", lease.Name)] = lease
+	}
+
+	for _, lease := range leases {
+		if _, err := o.addSection(ctx, "dhcp", "host", lease); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStaticLeases updates existing static DHCP leases on the OpenWRT
+// device, matched by name. Empty fields are removed from the lease.
+func (o *OpenWRT) UpdateStaticLeases(ctx context.Context, updateLeases []StaticLease) error {
+	currentLeases, records, err := o.leasesAndRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateLeases))
+	var notFound []string
+	for index, lease := range updateLeases {
+		if err := lease.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findStaticLease(currentLeases, lease.Name)
+		if !ok {
+			notFound = append(notFound, lease.Name)
+			continue
+		}
+		cfgs[index] = cfg
+
+		if conflicts := StaticLeaseConflicts(currentLeases, records, lease, cfg); len(conflicts) > 0 {
+			return fmt.Errorf("%w: lease %s: %s", ErrConflict, lease.Name, strings.Join(conflicts, ", "))
+		}
+		currentLeases[cfg] = lease
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("leases not found: %v", notFound)
+	}
+
+	for index, lease := range updateLeases {
+		if err := o.updateSection(ctx, "dhcp", cfgs[index], lease); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteStaticLeases deletes static DHCP leases from the OpenWRT device,
+// matched by name.
+func (o *OpenWRT) DeleteStaticLeases(ctx context.Context, deleteLeases []StaticLease) error {
+	currentLeases, err := o.GetStaticLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, lease := range deleteLeases {
+		cfg, ok := findStaticLease(currentLeases, lease.Name)
+		if !ok {
+			notFound = append(notFound, lease.Name)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("leases not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"dhcp", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"dhcp"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a static lease.
+func (l StaticLease) Validate() error {
+	if l.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if err := validateDNSName("name", l.Name); err != nil {
+		return err
+	}
+
+	if len(l.MAC) == 0 {
+		return fmt.Errorf("mac is required")
+	}
+
+	for _, mac := range l.MAC {
+		if err := validateMAC("mac", mac); err != nil {
+			return err
+		}
+	}
+
+	if l.IP != "" && l.IP != "ignore" {
+		if err := validateIPv4("ip", l.IP); err != nil {
+			return err
+		}
+	}
+
+	if err := validateLeaseTime("leasetime", l.LeaseTime); err != nil {
+		return err
+	}
+
+	for _, tag := range l.Tag {
+		if err := validateUciName("tag", tag); err != nil {
+			return err
+		}
+	}
+
+	return validateBool("dns", l.DNS)
+}
+
+// StaticLeaseConflicts describes why the lease clashes with the current
+// leases, other than the one in section ignore, and DNS records: another
+// lease using its name, MAC or IP, or an A record of its name pointing at a
+// different address.
+func StaticLeaseConflicts(leases map[string]StaticLease, records map[string]DNSRecord, lease StaticLease, ignore string) []string {
+	cfgs := make([]string, 0, len(leases))
+	for cfg := range leases {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	var conflicts []string
+	for _, cfg := range cfgs {
+		current := leases[cfg]
+		if cfg == ignore {
+			continue
+		}
+
+		if strings.EqualFold(current.Name, lease.Name) {
+			conflicts = append(conflicts, fmt.Sprintf("name is used by %s", cfg))
+		}
+
+		if lease.IP != "" && lease.IP != "ignore" && current.IP == lease.IP {
+			conflicts = append(conflicts, fmt.Sprintf("ip %s is leased to %s", lease.IP, current.Name))
+		}
+
+		for _, mac := range lease.MAC {
+			if slices.ContainsFunc(current.MAC, func(currentMAC string) bool {
+				return strings.EqualFold(currentMAC, mac)
+			}) {
+				conflicts = append(conflicts, fmt.Sprintf("mac %s is leased to %s", mac, current.Name))
+			}
+		}
+	}
+
+	if lease.IP == "" || lease.IP == "ignore" {
+		return conflicts
+	}
+
+	for _, record := range sortedDNSRecords(records) {
+		if record.Type == "A" && normalizeDNSName(record.Name) == normalizeDNSName(lease.Name) && record.IP != lease.IP {
+			conflicts = append(conflicts, fmt.Sprintf("dns record %s points at %s", record.Name, record.IP))
+		}
+	}
+
+	return conflicts
+}
+
+func (o *OpenWRT) leasesAndRecords(ctx context.Context) (map[string]StaticLease, map[string]DNSRecord, error) {
+	leases, err := o.GetStaticLeases(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err := o.GetDNSRecords(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return leases, records, nil
+}
+
+func findStaticLease(leases map[string]StaticLease, name string) (string, bool) {
+	for cfg, lease := range leases {
+		if strings.EqualFold(lease.Name, name) {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Static leases", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("dhcp", "host", "nas", map[string]any{
+			"name": "nas",
+			"mac":  "aa:bb:cc:dd:ee:01 aa:bb:cc:dd:ee:02",
+			"ip":   "192.168.1.10",
+		})
+		router.AddSection("dhcp", "domain", "", map[string]any{"name": "printer", "ip": "192.168.1.30"})
+	})
+
+	It("get leases", func() {
+		o := newFakeOpenWRT(router)
+		leases, err := o.GetStaticLeases(ctx)
+		Expect(err).To(BeNil())
+		Expect(leases).To(Equal(map[string]StaticLease{
+			"nas": {
+				Type: "host",
+				Name: "nas",
+				MAC:  UciList{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02"},
+				IP:   "192.168.1.10",
+			},
+		}))
+	})
+
+	It("create, update and delete a lease", func() {
+		o := newFakeOpenWRT(router)
+		lease := StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:dd:ee:03"}, IP: "192.168.1.20", LeaseTime: "12h"}
+		Expect(o.SetStaticLeases(ctx, []StaticLease{lease})).To(Succeed())
+		Expect(router.Sections("dhcp", "host")).To(ContainElement(And(
+			HaveKeyWithValue("name", "tv"),
+			HaveKeyWithValue("mac", []any{"aa:bb:cc:dd:ee:03"}),
+			HaveKeyWithValue("leasetime", "12h"),
+		)))
+
+		lease.LeaseTime = ""
+		lease.IP = "192.168.1.21"
+		Expect(o.UpdateStaticLeases(ctx, []StaticLease{lease})).To(Succeed())
+		Expect(router.Sections("dhcp", "host")).To(ContainElement(And(
+			HaveKeyWithValue("name", "tv"),
+			HaveKeyWithValue("ip", "192.168.1.21"),
+			Not(HaveKey("leasetime")),
+		)))
+
+		Expect(o.DeleteStaticLeases(ctx, []StaticLease{{Name: "tv"}})).To(Succeed())
+		Expect(router.Sections("dhcp", "host")).To(HaveLen(1))
+		Expect(router.Commits("dhcp")).To(Equal(3))
+	})
+
+	It("detect conflicts", func() {
+		o := newFakeOpenWRT(router)
+		err := o.SetStaticLeases(ctx, []StaticLease{
+			{Name: "printer", MAC: UciList{"AA:BB:CC:DD:EE:02"}, IP: "192.168.1.10"},
+		})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		Expect(err.Error()).To(Equal("conflict: lease printer: ip 192.168.1.10 is leased to nas, " +
+			"mac AA:BB:CC:DD:EE:02 is leased to nas, dns record printer points at 192.168.1.30"))
+		Expect(router.Commits("dhcp")).To(Equal(0))
+	})
+
+	It("validate", func() {
+		Expect(StaticLease{Name: "tv", MAC: UciList{"foobar"}}.Validate()).ToNot(Succeed())
+		Expect(StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:dd:ee:03"}, IP: "fd00::1"}.Validate()).ToNot(Succeed())
+		Expect(StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:dd:ee:03"}, LeaseTime: "12x"}.Validate()).ToNot(Succeed())
+		Expect(StaticLease{Name: "tv", MAC: UciList{"aa:bb:cc:*:*:*"}, IP: "192.168.1.2", LeaseTime: "infinite"}.Validate()).To(Succeed())
+	})
+})
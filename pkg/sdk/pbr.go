@@ -2,52 +2,423 @@ package sdk
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
-// GetPBRPolicies retrieves all Policy-Based Routing (PBR) policies from the OpenWRT device.
+// GetPBRPolicies retrieves all Policy-Based Routing (PBR) policies from the
+// OpenWRT device, leaving out the other sections of the pbr config.
 func (o *OpenWRT) GetPBRPolicies(ctx context.Context) (map[string]PBR, error) {
-	result, err := o.lucirpc.Uci(ctx, "get_all", []string{"pbr"})
+	return getSections[PBR](ctx, o, "pbr", "policy")
+}
+
+// EnablePBRPolicy enables or disables a specific PBR policy, referenced by
+// its name or section ID.
+func (o *OpenWRT) EnablePBRPolicy(ctx context.Context, policyName string, enabled bool) error {
+	_, err := o.EnablePBRPolicies(ctx, []string{policyName}, enabled)
+	return err
+}
+
+// EnablePBRPolicies enables or disables PBR policies, referenced by name or
+// section ID, in a single commit. It returns the references of the policies
+// whose state changed. Nothing is written when a reference matches no policy
+// or several policies.
+func (o *OpenWRT) EnablePBRPolicies(ctx context.Context, refs []string, enabled bool) ([]string, error) {
+	states := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		states[ref] = enabled
+	}
+
+	return o.setPBRStates(ctx, refs, states)
+}
+
+// setPBRStates sets the enabled state of the policies refs to their value in
+// states in a single commit, returning the references whose state changed.
+func (o *OpenWRT) setPBRStates(ctx context.Context, refs []string, states map[string]bool) ([]string, error) {
+	currentPolicies, err := o.GetPBRPolicies(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var cfgs map[string]PBR
-	err = json.Unmarshal([]byte(result), &cfgs)
-	if err != nil {
+	var (
+		cfgs     []string
+		values   []string
+		changed  []string
+		notFound []string
+	)
+	for _, ref := range refs {
+		cfg, err := resolvePBRPolicy(currentPolicies, ref)
+		if errors.Is(err, ErrSectionNotFound) {
+			notFound = append(notFound, ref)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// policies are enabled unless the option says otherwise
+		enabled := states[ref]
+		if policy := currentPolicies[cfg]; (policy.Enabled != "0") == enabled || slices.Contains(cfgs, cfg) {
+			continue
+		}
+
+		enableValue := "0"
+		if enabled {
+			enableValue = "1"
+		}
+		cfgs = append(cfgs, cfg)
+		values = append(values, enableValue)
+		changed = append(changed, ref)
+	}
+
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("%w: pbr policies %v", ErrSectionNotFound, notFound)
+	}
+
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+
+	for index, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "set", []string{"pbr", cfg, "enabled", values[index]}); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
 		return nil, err
 	}
 
-	return cfgs, nil
+	return changed, nil
 }
 
-// EnablePBRPolicy enables or disables a specific PBR policy by its name.
-func (o *OpenWRT) EnablePBRPolicy(ctx context.Context, policyName string, enabled bool) error {
+// SetPBRPolicies adds new PBR policies to the OpenWRT device. Nothing is
+// written when a policy is invalid or its name is already used.
+func (o *OpenWRT) SetPBRPolicies(ctx context.Context, policies []PBR) error {
 	currentPolicies, err := o.GetPBRPolicies(ctx)
 	if err != nil {
 		return err
 	}
 
-	for cfg, currentPolicy := range currentPolicies {
-		if currentPolicy.Name != policyName {
-			continue
+	for index, policy := range policies {
+		if err := o.validatePBRPolicy(ctx, policy); err != nil {
+			return err
 		}
 
-		enableValue := "0"
-		if enabled {
-			enableValue = "1"
+		if _, ok := findPBRPolicy(currentPolicies, policy.Name); ok {
+			return fmt.Errorf("%w: pbr policy %s already exists", ErrConflict, policy.Name)
+		}
+		policy.Type = "policy"
+		currentPolicies[fmt.Sprintf("new policy %d", index)] = policy
+	}
+
+	for _, policy := range policies {
+		if _, err := o.addSection(ctx, "pbr", "policy", policy); err != nil {
+			return err
 		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
 
-		_, err := o.lucirpc.Uci(ctx, "set", []string{"pbr", cfg, "enabled", enableValue})
+// UpdatePBRPolicies updates existing PBR policies on the OpenWRT device,
+// matched by section ID or unique name, where a policy matched by section ID
+// keeps its name. Empty fields are removed from the policy.
+func (o *OpenWRT) UpdatePBRPolicies(ctx context.Context, updatePolicies []PBR) error {
+	currentPolicies, err := o.GetPBRPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	updatePolicies = slices.Clone(updatePolicies)
+	cfgs := make([]string, len(updatePolicies))
+	var notFound []string
+	for index, policy := range updatePolicies {
+		if err := o.validatePBRPolicy(ctx, policy); err != nil {
+			return err
+		}
+
+		cfg, err := resolvePBRPolicy(currentPolicies, policy.Name)
+		if errors.Is(err, ErrSectionNotFound) {
+			notFound = append(notFound, policy.Name)
+			continue
+		}
 		if err != nil {
 			return err
 		}
 
-		_, err = o.lucirpc.Uci(ctx, "commit", []string{"pbr"})
+		if cfg == policy.Name {
+			updatePolicies[index].Name = currentPolicies[cfg].Name
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr policies not found: %v", notFound)
+	}
+
+	for index, policy := range updatePolicies {
+		if err := o.updateSection(ctx, "pbr", cfgs[index], policy); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePBRPolicies deletes PBR policies from the OpenWRT device by section
+// ID or unique name.
+func (o *OpenWRT) DeletePBRPolicies(ctx context.Context, names []string) error {
+	currentPolicies, err := o.GetPBRPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, name := range names {
+		cfg, err := resolvePBRPolicy(currentPolicies, name)
+		if errors.Is(err, ErrSectionNotFound) {
+			notFound = append(notFound, name)
+			continue
+		}
 		if err != nil {
 			return err
 		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr policies not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"pbr", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenamePBRPolicy changes the name of an existing PBR policy, referenced by
+// section ID or unique name.
+func (o *OpenWRT) RenamePBRPolicy(ctx context.Context, oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	currentPolicies, err := o.GetPBRPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := resolvePBRPolicy(currentPolicies, oldName)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := findPBRPolicy(currentPolicies, newName); ok {
+		return fmt.Errorf("%w: pbr policy %s already exists", ErrConflict, newName)
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "set", []string{"pbr", cfg, "name", newName}); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a PBR policy which do not depend on the
+// network interfaces of the device.
+func (p PBR) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if p.Interface == "" {
+		return fmt.Errorf("interface is required")
+	}
+
+	if p.SrcAddr == "" && p.SrcPort == "" && p.DsrAddr == "" && p.DestPort == "" {
+		return fmt.Errorf("at least one of src_addr, src_port, dest_addr or dest_port is required")
+	}
+
+	for _, addr := range pbrValues(p.SrcAddr) {
+		if err := validatePBRAddress("src_addr", addr, true); err != nil {
+			return err
+		}
+	}
+
+	for _, addr := range pbrValues(p.DsrAddr) {
+		if err := validatePBRAddress("dest_addr", addr, false); err != nil {
+			return err
+		}
+	}
+
+	for _, port := range pbrValues(p.SrcPort) {
+		if err := validatePBRPort("src_port", port); err != nil {
+			return err
+		}
+	}
+
+	for _, port := range pbrValues(p.DestPort) {
+		if err := validatePBRPort("dest_port", port); err != nil {
+			return err
+		}
+	}
+
+	for _, proto := range pbrValues(p.Proto) {
+		if !slices.Contains([]string{"all", "tcp", "udp", "icmp"}, proto) {
+			return fmt.Errorf("invalid proto: %s, expected all, tcp, udp or icmp", proto)
+		}
+	}
+
+	if p.Chain != "" && !slices.Contains([]string{"prerouting", "forward", "input", "output", "postrouting"}, p.Chain) {
+		return fmt.Errorf("invalid chain: %s, expected prerouting, forward, input, output or postrouting", p.Chain)
+	}
+
+	return validateBool("enabled", p.Enabled)
+}
+
+// validatePBRPolicy checks the policy and that its interface exists on the device.
+func (o *OpenWRT) validatePBRPolicy(ctx context.Context, policy PBR) error {
+	interfaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checkPBRPolicy(policy, interfaces)
+}
+
+// checkPBRPolicy checks the policy and that its interface is in interfaces.
+func checkPBRPolicy(policy PBR, interfaces map[string]Interface) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	if policy.Interface == "ignore" {
+		return nil
+	}
+
+	return checkInterfaceNames(interfaces, policy.Interface)
+}
+
+// validatePBRAddress checks an IP address, subnet, domain name or @set
+// reference to a firewall set, optionally negated with !, and also a MAC
+// address for source addresses.
+func validatePBRAddress(field, addr string, source bool) error {
+	addr = strings.TrimPrefix(addr, "!")
+	if set, ok := strings.CutPrefix(addr, "@"); ok {
+		return validateUciName(field, set)
+	}
+
+	if _, err := netip.ParsePrefix(addr); err == nil {
+		return nil
+	}
+
+	if _, err := netip.ParseAddr(addr); err == nil {
+		return nil
+	}
+
+	if source && validateMAC(field, addr) == nil {
+		return nil
+	}
+
+	if validateDNSName(field, addr) == nil && strings.ContainsFunc(addr, unicode.IsLetter) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid %s: %s", field, addr)
+}
+
+// validatePBRPort checks a port or a first-last port range, optionally negated with !.
+func validatePBRPort(field, port string) error {
+	port = strings.TrimPrefix(port, "!")
+	first, last, isRange := strings.Cut(port, "-")
+	if err := validateUint(field, first, 1, 65535); err != nil || first == "" {
+		return fmt.Errorf("invalid %s: %s", field, port)
+	}
+
+	if !isRange {
+		return nil
+	}
+
+	if err := validateUint(field, last, 1, 65535); err != nil || last == "" {
+		return fmt.Errorf("invalid %s: %s", field, port)
+	}
+
+	firstPort, _ := strconv.Atoi(first)
+	lastPort, _ := strconv.Atoi(last)
+	if lastPort < firstPort {
+		return fmt.Errorf("invalid %s: %s", field, port)
 	}
 
 	return nil
 }
+
+// pbrValues splits an option holding values separated by spaces or commas.
+func pbrValues(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' })
+}
+
+// resolvePBRPolicy returns the section of the policy with the section ID or,
+// failing that, the unique policy with the name.
+func resolvePBRPolicy(policies map[string]PBR, ref string) (string, error) {
+	if policy, ok := policies[ref]; ok && policy.Type == "policy" {
+		return ref, nil
+	}
+
+	var cfgs []string
+	for cfg, policy := range policies {
+		if policy.Type == "policy" && policy.Name == ref {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	slices.Sort(cfgs)
+
+	switch len(cfgs) {
+	case 0:
+		return "", fmt.Errorf("%w: pbr policy %s", ErrSectionNotFound, ref)
+	case 1:
+		return cfgs[0], nil
+	default:
+		return "", fmt.Errorf("%w: pbr policy %s matches sections %v", ErrAmbiguous, ref, cfgs)
+	}
+}
+
+func findPBRPolicy(policies map[string]PBR, name string) (string, bool) {
+	for cfg, policy := range policies {
+		if policy.Type == "policy" && policy.Name == name {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
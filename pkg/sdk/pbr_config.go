@@ -0,0 +1,211 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	pbrInitScript     = "/etc/init.d/pbr"
+	pbrStatusCommand  = `ubus call luci.pbr getInitStatus '{"name":"pbr"}'`
+	pbrEnabledCommand = pbrInitScript + " enabled && echo enabled"
+	pbrServiceCommand = `ubus call service list '{"name":"pbr"}'`
+)
+
+var pbrResolverSets = []string{"none", "dnsmasq.ipset", "dnsmasq.nftset", "unbound.ipset", "unbound.nftset"}
+
+// GetPBRConfig retrieves the global PBR settings from the OpenWRT device.
+func (o *OpenWRT) GetPBRConfig(ctx context.Context) (PBRConfig, error) {
+	_, cfg, err := o.pbrConfig(ctx)
+	return cfg, err
+}
+
+// UpdatePBRConfig updates the global PBR settings on the OpenWRT device.
+// Empty fields are removed, letting PBR use its defaults, so callers usually
+// modify the result of GetPBRConfig. The service must be restarted for the
+// settings to apply.
+func (o *OpenWRT) UpdatePBRConfig(ctx context.Context, cfg PBRConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	name, _, err := o.pbrConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := o.updateSection(ctx, "pbr", name, cfg); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StartPBR starts the PBR service.
+func (o *OpenWRT) StartPBR(ctx context.Context) error {
+	_, err := o.exec(ctx, pbrInitScript+" start")
+	return err
+}
+
+// StopPBR stops the PBR service.
+func (o *OpenWRT) StopPBR(ctx context.Context) error {
+	_, err := o.exec(ctx, pbrInitScript+" stop")
+	return err
+}
+
+// RestartPBR restarts the PBR service, applying the committed configuration.
+func (o *OpenWRT) RestartPBR(ctx context.Context) error {
+	_, err := o.exec(ctx, pbrInitScript+" restart")
+	return err
+}
+
+// GetPBRStatus retrieves the state of the PBR service. The status reported
+// by the LuCI PBR application is used when available, otherwise the init
+// script and procd are queried directly.
+func (o *OpenWRT) GetPBRStatus(ctx context.Context) (PBRStatus, error) {
+	output, err := o.exec(ctx, pbrStatusCommand)
+	if err != nil {
+		return PBRStatus{}, err
+	}
+
+	if status, ok := parseLuciPBRStatus(output); ok {
+		return status, nil
+	}
+
+	var status PBRStatus
+	output, err = o.exec(ctx, pbrEnabledCommand)
+	if err != nil {
+		return PBRStatus{}, err
+	}
+	status.Enabled = strings.TrimSpace(output) == "enabled"
+
+	output, err = o.exec(ctx, pbrServiceCommand)
+	if err != nil {
+		return PBRStatus{}, err
+	}
+
+	var services map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(output), &services); err == nil {
+		_, status.Running = services["pbr"]
+	}
+
+	return status, nil
+}
+
+// Validate checks the fields of the global PBR settings.
+func (c PBRConfig) Validate() error {
+	bools := [][]string{
+		{"enabled", c.Enabled},
+		{"strict_enforcement", c.StrictEnforcement},
+		{"ipv6_enabled", c.IPv6Enabled},
+		{"webui_show_ignore_target", c.WebUIShowIgnoreTarget},
+	}
+	for _, option := range bools {
+		if err := validateBool(option[0], option[1]); err != nil {
+			return err
+		}
+	}
+
+	if c.ResolverSet != "" && !slices.Contains(pbrResolverSets, c.ResolverSet) {
+		return fmt.Errorf("invalid resolver_set: %s, expected %s", c.ResolverSet, strings.Join(pbrResolverSets, ", "))
+	}
+
+	for _, iface := range slices.Concat(c.SupportedInterface, c.IgnoredInterface) {
+		if err := validateUciName("interface", iface); err != nil {
+			return err
+		}
+	}
+
+	if err := validateUint("boot_timeout", c.BootTimeout, 0, 3600); err != nil {
+		return err
+	}
+
+	if err := validateUint("verbosity", c.Verbosity, 0, 2); err != nil {
+		return err
+	}
+
+	for _, proto := range c.WebUISupportedProtocol {
+		if !slices.Contains([]string{"all", "tcp", "udp", "tcp udp", "icmp"}, proto) {
+			return fmt.Errorf("invalid webui_supported_protocol: %s", proto)
+		}
+	}
+
+	return nil
+}
+
+// pbrConfig returns the name and content of the config section of PBR.
+func (o *OpenWRT) pbrConfig(ctx context.Context) (string, PBRConfig, error) {
+	cfgs, err := getSections[PBRConfig](ctx, o, "pbr", "config")
+	if err != nil {
+		return "", PBRConfig{}, err
+	}
+
+	for name, cfg := range cfgs {
+		return name, cfg, nil
+	}
+
+	return "", PBRConfig{}, fmt.Errorf("%w: pbr config", ErrSectionNotFound)
+}
+
+// parseLuciPBRStatus parses the output of the luci.pbr getInitStatus ubus
+// call, reporting false when the call is not available.
+func parseLuciPBRStatus(output string) (PBRStatus, bool) {
+	var result map[string]struct {
+		Enabled  bool            `json:"enabled"`
+		Running  bool            `json:"running"`
+		Version  string          `json:"version"`
+		Errors   json.RawMessage `json:"errors"`
+		Warnings json.RawMessage `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return PBRStatus{}, false
+	}
+
+	status, ok := result["pbr"]
+	if !ok {
+		return PBRStatus{}, false
+	}
+
+	return PBRStatus{
+		Enabled:  status.Enabled,
+		Running:  status.Running,
+		Version:  status.Version,
+		Errors:   pbrMessages(status.Errors),
+		Warnings: pbrMessages(status.Warnings),
+	}, true
+}
+
+// pbrMessages decodes the errors or warnings of the PBR status, reported as
+// strings or as objects with a code and extra information.
+func pbrMessages(data json.RawMessage) []string {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var messages []string
+	for _, item := range raw {
+		var message string
+		if err := json.Unmarshal(item, &message); err == nil {
+			messages = append(messages, message)
+			continue
+		}
+
+		var coded struct {
+			Code string `json:"code"`
+			Info string `json:"info"`
+		}
+		if err := json.Unmarshal(item, &coded); err == nil && coded.Code != "" {
+			messages = append(messages, strings.TrimSpace(coded.Code+" "+coded.Info))
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("PBR config", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("pbr", "config", "config", map[string]any{
+			"enabled":             "1",
+			"resolver_set":        "dnsmasq.nftset",
+			"supported_interface": []any{"wg0"},
+		})
+	})
+
+	It("get and update the config", func() {
+		o := newFakeOpenWRT(router)
+		cfg, err := o.GetPBRConfig(ctx)
+		Expect(err).To(BeNil())
+		Expect(cfg).To(Equal(PBRConfig{
+			Type:               "config",
+			Enabled:            "1",
+			ResolverSet:        "dnsmasq.nftset",
+			SupportedInterface: UciList{"wg0"},
+		}))
+
+		cfg.StrictEnforcement = "1"
+		cfg.SupportedInterface = nil
+		Expect(o.UpdatePBRConfig(ctx, cfg)).To(Succeed())
+		Expect(router.Sections("pbr", "config")).To(ConsistOf(And(
+			HaveKeyWithValue("strict_enforcement", "1"),
+			Not(HaveKey("supported_interface")),
+		)))
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		cfg.ResolverSet = "bind"
+		Expect(o.UpdatePBRConfig(ctx, cfg)).ToNot(Succeed())
+	})
+
+	It("control the service", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.StopPBR(ctx)).To(Succeed())
+		Expect(o.StartPBR(ctx)).To(Succeed())
+		Expect(o.RestartPBR(ctx)).To(Succeed())
+		Expect(router.History()).To(Equal([]string{
+			"/etc/init.d/pbr stop",
+			"/etc/init.d/pbr start",
+			"/etc/init.d/pbr restart",
+		}))
+	})
+
+	It("get the status reported by luci", func() {
+		router.SetOutput(pbrStatusCommand, `{"pbr": {"enabled": true, "running": true, "version": "1.1.7-r1",
+			"errors": [{"code": "errorNoGateways", "info": ""}], "warnings": ["strict mode"]}}`)
+		o := newFakeOpenWRT(router)
+		Expect(o.GetPBRStatus(ctx)).To(Equal(PBRStatus{
+			Enabled:  true,
+			Running:  true,
+			Version:  "1.1.7-r1",
+			Errors:   []string{"errorNoGateways"},
+			Warnings: []string{"strict mode"},
+		}))
+	})
+
+	It("get the status from the init script and procd", func() {
+		router.SetOutput(pbrEnabledCommand, "enabled\n")
+		router.SetOutput(pbrServiceCommand, `{"pbr": {"instances": {}}}`)
+		o := newFakeOpenWRT(router)
+		Expect(o.GetPBRStatus(ctx)).To(Equal(PBRStatus{Enabled: true, Running: true}))
+
+		router.SetOutput(pbrEnabledCommand, "")
+		router.SetOutput(pbrServiceCommand, `{}`)
+		Expect(o.GetPBRStatus(ctx)).To(Equal(PBRStatus{}))
+	})
+})
@@ -0,0 +1,166 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// GetPBRDNSPolicies retrieves the PBR DNS policies from the OpenWRT device,
+// keyed by section name.
+func (o *OpenWRT) GetPBRDNSPolicies(ctx context.Context) (map[string]PBRDNSPolicy, error) {
+	return getSections[PBRDNSPolicy](ctx, o, "pbr", "dns_policy")
+}
+
+// SetPBRDNSPolicies adds new PBR DNS policies to the OpenWRT device. Nothing
+// is written when a policy is invalid or its name is already used.
+func (o *OpenWRT) SetPBRDNSPolicies(ctx context.Context, policies []PBRDNSPolicy) error {
+	currentPolicies, err := o.GetPBRDNSPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findPBRDNSPolicy(currentPolicies, policy.Name); ok {
+			return fmt.Errorf("%w: pbr dns policy %s already exists", ErrConflict, policy.Name)
+		}
+		currentPolicies[fmt.Sprintf("new policy %d", index)] = policy
+	}
+
+	for _, policy := range policies {
+		if _, err := o.addSection(ctx, "pbr", "dns_policy", policy); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePBRDNSPolicies updates existing PBR DNS policies on the OpenWRT
+// device, matched by name. Empty fields are removed from the policy.
+func (o *OpenWRT) UpdatePBRDNSPolicies(ctx context.Context, updatePolicies []PBRDNSPolicy) error {
+	currentPolicies, err := o.GetPBRDNSPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updatePolicies))
+	var notFound []string
+	for index, policy := range updatePolicies {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findPBRDNSPolicy(currentPolicies, policy.Name)
+		if !ok {
+			notFound = append(notFound, policy.Name)
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr dns policies not found: %v", notFound)
+	}
+
+	for index, policy := range updatePolicies {
+		if err := o.updateSection(ctx, "pbr", cfgs[index], policy); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePBRDNSPolicies deletes PBR DNS policies from the OpenWRT device by name.
+func (o *OpenWRT) DeletePBRDNSPolicies(ctx context.Context, names []string) error {
+	currentPolicies, err := o.GetPBRDNSPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, name := range names {
+		cfg, ok := findPBRDNSPolicy(currentPolicies, name)
+		if !ok {
+			notFound = append(notFound, name)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr dns policies not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"pbr", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a PBR DNS policy.
+func (p PBRDNSPolicy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if p.SrcAddr == "" {
+		return fmt.Errorf("src_addr is required")
+	}
+
+	for _, addr := range pbrValues(p.SrcAddr) {
+		if err := validatePBRAddress("src_addr", addr, true); err != nil {
+			return err
+		}
+	}
+
+	// the resolver is an address or the name of an interface using its DNS servers
+	if p.DestDNS == "" {
+		return fmt.Errorf("dest_dns is required")
+	}
+
+	for _, dns := range pbrValues(p.DestDNS) {
+		if _, err := netip.ParseAddr(dns); err != nil && validateUciName("dest_dns", dns) != nil {
+			return fmt.Errorf("invalid dest_dns: %s", dns)
+		}
+	}
+
+	return validateBool("enabled", p.Enabled)
+}
+
+func findPBRDNSPolicy(policies map[string]PBRDNSPolicy, name string) (string, bool) {
+	for cfg, policy := range policies {
+		if policy.Name == name {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
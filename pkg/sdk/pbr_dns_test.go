@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("PBR sections", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("pbr", "config", "config", map[string]any{"enabled": "1"})
+		router.AddSection("pbr", "include", "", map[string]any{"path": "/usr/share/pbr/pbr.user.aws", "enabled": "0"})
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "vpn", "src_addr": "192.168.1.10", "interface": "wg0"})
+		router.AddSection("pbr", "dns_policy", "", map[string]any{"name": "kids", "src_addr": "192.168.1.50", "dest_dns": "1.1.1.3"})
+	})
+
+	It("get only the sections of each type", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.GetPBRPolicies(ctx)).To(Equal(map[string]PBR{
+			"cfg2": {Type: "policy", Name: "vpn", SrcAddr: "192.168.1.10", Interface: "wg0"},
+		}))
+		Expect(o.GetPBRDNSPolicies(ctx)).To(Equal(map[string]PBRDNSPolicy{
+			"cfg3": {Type: "dns_policy", Name: "kids", SrcAddr: "192.168.1.50", DestDNS: "1.1.1.3"},
+		}))
+		Expect(o.GetPBRIncludes(ctx)).To(Equal(map[string]PBRInclude{
+			"cfg1": {Type: "include", Path: "/usr/share/pbr/pbr.user.aws", Enabled: "0"},
+		}))
+	})
+
+	It("create, update and delete a dns policy", func() {
+		o := newFakeOpenWRT(router)
+		policy := PBRDNSPolicy{Name: "guests", SrcAddr: "192.168.2.0/24", DestDNS: "wan"}
+		Expect(o.SetPBRDNSPolicies(ctx, []PBRDNSPolicy{policy})).To(Succeed())
+		Expect(router.Sections("pbr", "dns_policy")).To(ContainElement(HaveKeyWithValue("dest_dns", "wan")))
+
+		err := o.SetPBRDNSPolicies(ctx, []PBRDNSPolicy{policy})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		policy.DestDNS = "9.9.9.9"
+		policy.Enabled = "0"
+		Expect(o.UpdatePBRDNSPolicies(ctx, []PBRDNSPolicy{policy})).To(Succeed())
+		Expect(router.Sections("pbr", "dns_policy")).To(ContainElement(And(
+			HaveKeyWithValue("name", "guests"),
+			HaveKeyWithValue("dest_dns", "9.9.9.9"),
+			HaveKeyWithValue("enabled", "0"),
+		)))
+
+		Expect(o.DeletePBRDNSPolicies(ctx, []string{"guests", "kids"})).To(Succeed())
+		Expect(router.Sections("pbr", "dns_policy")).To(BeEmpty())
+		Expect(router.Sections("pbr", "policy")).To(HaveLen(1))
+		Expect(o.DeletePBRDNSPolicies(ctx, []string{"kids"})).To(MatchError("pbr dns policies not found: [kids]"))
+		Expect(router.Commits("pbr")).To(Equal(3))
+	})
+
+	It("create, update and delete an include", func() {
+		o := newFakeOpenWRT(router)
+		include := PBRInclude{Path: "/etc/pbr.user.sh"}
+		Expect(o.SetPBRIncludes(ctx, []PBRInclude{include})).To(Succeed())
+		Expect(router.Sections("pbr", "include")).To(HaveLen(2))
+
+		include.Enabled = "0"
+		Expect(o.UpdatePBRIncludes(ctx, []PBRInclude{include})).To(Succeed())
+		Expect(router.Sections("pbr", "include")).To(HaveEach(HaveKeyWithValue("enabled", "0")))
+
+		Expect(o.DeletePBRIncludes(ctx, []string{"/etc/pbr.user.sh"})).To(Succeed())
+		Expect(router.Sections("pbr", "include")).To(HaveLen(1))
+		Expect(o.DeletePBRIncludes(ctx, []string{"/etc/pbr.user.sh"})).To(MatchError("pbr includes not found: [/etc/pbr.user.sh]"))
+	})
+
+	It("validate", func() {
+		Expect(PBRDNSPolicy{Name: "kids", DestDNS: "1.1.1.3"}.Validate()).ToNot(Succeed())
+		Expect(PBRDNSPolicy{Name: "kids", SrcAddr: "192.168.1.50"}.Validate()).ToNot(Succeed())
+		Expect(PBRDNSPolicy{Name: "kids", SrcAddr: "192.168.1.50", DestDNS: "1.1.1.x"}.Validate()).ToNot(Succeed())
+		Expect(PBRInclude{Path: "pbr.user.sh"}.Validate()).ToNot(Succeed())
+		Expect(PBRInclude{Path: "/etc/../pbr.user.sh"}.Validate()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+)
+
+// GetPBRIncludes retrieves the user scripts run by PBR from the OpenWRT
+// device, keyed by section name.
+func (o *OpenWRT) GetPBRIncludes(ctx context.Context) (map[string]PBRInclude, error) {
+	return getSections[PBRInclude](ctx, o, "pbr", "include")
+}
+
+// SetPBRIncludes adds user scripts to PBR on the OpenWRT device. Nothing is
+// written when a script is invalid or already included.
+func (o *OpenWRT) SetPBRIncludes(ctx context.Context, includes []PBRInclude) error {
+	currentIncludes, err := o.GetPBRIncludes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, include := range includes {
+		if err := include.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findPBRInclude(currentIncludes, include.Path); ok {
+			return fmt.Errorf("%w: pbr include %s already exists", ErrConflict, include.Path)
+		}
+		currentIncludes[fmt.Sprintf("new include %d", index)] = include
+	}
+
+	for _, include := range includes {
+		if _, err := o.addSection(ctx, "pbr", "include", include); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePBRIncludes updates existing PBR user scripts on the OpenWRT device,
+// matched by path.
+func (o *OpenWRT) UpdatePBRIncludes(ctx context.Context, updateIncludes []PBRInclude) error {
+	currentIncludes, err := o.GetPBRIncludes(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateIncludes))
+	var notFound []string
+	for index, include := range updateIncludes {
+		if err := include.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findPBRInclude(currentIncludes, include.Path)
+		if !ok {
+			notFound = append(notFound, include.Path)
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr includes not found: %v", notFound)
+	}
+
+	for index, include := range updateIncludes {
+		if err := o.updateSection(ctx, "pbr", cfgs[index], include); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePBRIncludes removes user scripts from PBR on the OpenWRT device by path.
+func (o *OpenWRT) DeletePBRIncludes(ctx context.Context, paths []string) error {
+	currentIncludes, err := o.GetPBRIncludes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, includePath := range paths {
+		cfg, ok := findPBRInclude(currentIncludes, includePath)
+		if !ok {
+			notFound = append(notFound, includePath)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("pbr includes not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"pbr", cfg}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks the fields of a PBR user script.
+func (i PBRInclude) Validate() error {
+	if i.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+
+	if !path.IsAbs(i.Path) || path.Clean(i.Path) != i.Path {
+		return fmt.Errorf("invalid path: %s, expected a clean absolute path", i.Path)
+	}
+
+	return validateBool("enabled", i.Enabled)
+}
+
+func findPBRInclude(includes map[string]PBRInclude, includePath string) (string, bool) {
+	for cfg, include := range includes {
+		if include.Path == includePath {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
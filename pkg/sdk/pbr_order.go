@@ -0,0 +1,171 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// GetOrderedPBRPolicies retrieves the PBR policies from the OpenWRT device in
+// the order PBR evaluates them.
+func (o *OpenWRT) GetOrderedPBRPolicies(ctx context.Context) ([]OrderedPBR, error) {
+	policies, _, err := o.pbrOrder(ctx)
+	return policies, err
+}
+
+// MovePBRPolicy moves a policy, referenced by name or section ID, to a
+// position among the policies, 0 being the first one evaluated. Positions
+// past the end move the policy to the bottom.
+func (o *OpenWRT) MovePBRPolicy(ctx context.Context, ref string, position int) error {
+	if position < 0 {
+		return fmt.Errorf("invalid position: %d", position)
+	}
+
+	return o.movePBRPolicy(ctx, ref, func([]OrderedPBR) (int, error) {
+		return position, nil
+	})
+}
+
+// MovePBRPolicyBefore moves a policy right before another one.
+func (o *OpenWRT) MovePBRPolicyBefore(ctx context.Context, ref, other string) error {
+	return o.movePBRPolicy(ctx, ref, func(others []OrderedPBR) (int, error) {
+		return pbrPosition(others, other)
+	})
+}
+
+// MovePBRPolicyAfter moves a policy right after another one.
+func (o *OpenWRT) MovePBRPolicyAfter(ctx context.Context, ref, other string) error {
+	return o.movePBRPolicy(ctx, ref, func(others []OrderedPBR) (int, error) {
+		position, err := pbrPosition(others, other)
+		return position + 1, err
+	})
+}
+
+// MovePBRPolicyToTop makes a policy the first one evaluated.
+func (o *OpenWRT) MovePBRPolicyToTop(ctx context.Context, ref string) error {
+	return o.MovePBRPolicy(ctx, ref, 0)
+}
+
+// MovePBRPolicyToBottom makes a policy the last one evaluated.
+func (o *OpenWRT) MovePBRPolicyToBottom(ctx context.Context, ref string) error {
+	return o.movePBRPolicy(ctx, ref, func(others []OrderedPBR) (int, error) {
+		return len(others), nil
+	})
+}
+
+// movePBRPolicy moves the policy ref to the position returned by target,
+// computed among the other policies, with a single reorder and commit.
+func (o *OpenWRT) movePBRPolicy(ctx context.Context, ref string, target func([]OrderedPBR) (int, error)) error {
+	policies, sections, err := o.pbrOrder(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := resolvePBRPolicy(pbrSections(policies), ref)
+	if err != nil {
+		return err
+	}
+
+	others := slices.DeleteFunc(slices.Clone(policies), func(policy OrderedPBR) bool {
+		return policy.Section == cfg
+	})
+	sections = slices.DeleteFunc(sections, func(section string) bool { return section == cfg })
+
+	position, err := target(others)
+	if err != nil {
+		return err
+	}
+
+	// uci reorder takes the index among every section of the config, once
+	// the moved section is taken out
+	var index int
+	switch {
+	case position < len(others):
+		index = slices.Index(sections, others[position].Section)
+	case len(others) > 0:
+		index = slices.Index(sections, others[len(others)-1].Section) + 1
+	default:
+		return nil
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "reorder", []string{"pbr", cfg, strconv.Itoa(index)}); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// pbrOrder returns the policies in order and the names of every section of
+// the pbr config in order.
+func (o *OpenWRT) pbrOrder(ctx context.Context) ([]OrderedPBR, []string, error) {
+	result, err := o.lucirpc.Uci(ctx, "get_all", []string{"pbr"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(result), &raw); err != nil {
+		return nil, nil, err
+	}
+
+	indexes := make(map[string]int, len(raw))
+	var policies []OrderedPBR
+	for name, data := range raw {
+		var meta struct {
+			Type  string `json:".type"`
+			Index int    `json:".index"`
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, nil, err
+		}
+		indexes[name] = meta.Index
+
+		if meta.Type != "policy" {
+			continue
+		}
+
+		policy := OrderedPBR{Section: name}
+		if err := json.Unmarshal(data, &policy.PBR); err != nil {
+			return nil, nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	sections := make([]string, 0, len(indexes))
+	for name := range indexes {
+		sections = append(sections, name)
+	}
+	slices.SortFunc(sections, func(a, b string) int { return indexes[a] - indexes[b] })
+
+	slices.SortFunc(policies, func(a, b OrderedPBR) int { return indexes[a.Section] - indexes[b.Section] })
+	for index := range policies {
+		policies[index].Index = index
+	}
+
+	return policies, sections, nil
+}
+
+// pbrPosition returns the position of the policy ref among policies.
+func pbrPosition(policies []OrderedPBR, ref string) (int, error) {
+	cfg, err := resolvePBRPolicy(pbrSections(policies), ref)
+	if err != nil {
+		return 0, err
+	}
+
+	return slices.IndexFunc(policies, func(policy OrderedPBR) bool { return policy.Section == cfg }), nil
+}
+
+func pbrSections(policies []OrderedPBR) map[string]PBR {
+	sections := make(map[string]PBR, len(policies))
+	for _, policy := range policies {
+		sections[policy.Section] = policy.PBR
+	}
+
+	return sections
+}
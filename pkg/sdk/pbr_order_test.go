@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("PBR policy order", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	names := func(o *OpenWRT) []string {
+		policies, err := o.GetOrderedPBRPolicies(ctx)
+		Expect(err).To(BeNil())
+
+		var result []string
+		for index, policy := range policies {
+			Expect(policy.Index).To(Equal(index))
+			result = append(result, policy.Name)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("pbr", "config", "config", nil)
+		for _, name := range []string{"a", "b", "c"} {
+			router.AddSection("pbr", "policy", "", map[string]any{"name": name, "interface": "wan"})
+		}
+		router.AddSection("pbr", "include", "", map[string]any{"path": "/etc/pbr.user.sh"})
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "d", "interface": "wan"})
+	})
+
+	It("list policies in order", func() {
+		o := newFakeOpenWRT(router)
+		policies, err := o.GetOrderedPBRPolicies(ctx)
+		Expect(err).To(BeNil())
+		Expect(policies[0]).To(Equal(OrderedPBR{
+			Section: "cfg1",
+			Index:   0,
+			PBR:     PBR{Type: "policy", Name: "a", Interface: "wan"},
+		}))
+		Expect(names(o)).To(Equal([]string{"a", "b", "c", "d"}))
+	})
+
+	It("move policies with one commit each", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.MovePBRPolicyToBottom(ctx, "a")).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"b", "c", "d", "a"}))
+
+		Expect(o.MovePBRPolicyToTop(ctx, "d")).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"d", "b", "c", "a"}))
+
+		Expect(o.MovePBRPolicyBefore(ctx, "a", "b")).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"d", "a", "b", "c"}))
+
+		Expect(o.MovePBRPolicyAfter(ctx, "d", "c")).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"a", "b", "c", "d"}))
+
+		Expect(o.MovePBRPolicy(ctx, "cfg1", 2)).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"b", "c", "a", "d"}))
+
+		Expect(o.MovePBRPolicy(ctx, "b", 10)).To(Succeed())
+		Expect(names(o)).To(Equal([]string{"c", "a", "d", "b"}))
+		Expect(router.Commits("pbr")).To(Equal(6))
+
+		// the other sections keep their place
+		Expect(router.Sections("pbr", "")[0]).To(HaveKeyWithValue(".type", "config"))
+	})
+
+	It("report unknown policies", func() {
+		o := newFakeOpenWRT(router)
+		err := o.MovePBRPolicyBefore(ctx, "a", "e")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		err = o.MovePBRPolicyToTop(ctx, "e")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		Expect(o.MovePBRPolicy(ctx, "a", -1)).ToNot(Succeed())
+		Expect(router.Commits("pbr")).To(Equal(0))
+	})
+})
@@ -0,0 +1,222 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// PBRScheduler enables the PBR policies inside their schedule windows and
+// disables them outside, either once with Apply or continuously with Run.
+type PBRScheduler struct {
+	client    *OpenWRT
+	schedules []PBRSchedule
+	location  *time.Location
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewPBRScheduler validates the schedules and returns a scheduler checking
+// them every minute, in the local time zone. A nil logger uses the default one.
+func NewPBRScheduler(client *OpenWRT, schedules []PBRSchedule, logger *slog.Logger) (*PBRScheduler, error) {
+	policies := make(map[string]bool, len(schedules))
+	for _, schedule := range schedules {
+		if err := schedule.Validate(); err != nil {
+			return nil, err
+		}
+
+		if policies[schedule.Policy] {
+			return nil, fmt.Errorf("%w: pbr policy %s is scheduled twice", ErrConflict, schedule.Policy)
+		}
+		policies[schedule.Policy] = true
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PBRScheduler{
+		client:    client,
+		schedules: schedules,
+		location:  time.Local,
+		interval:  time.Minute,
+		logger:    logger,
+	}, nil
+}
+
+// SetLocation sets the time zone the windows are expressed in.
+func (s *PBRScheduler) SetLocation(location *time.Location) {
+	s.location = location
+}
+
+// SetInterval sets how often Run reconciles the policies.
+func (s *PBRScheduler) SetInterval(interval time.Duration) {
+	s.interval = interval
+}
+
+// Desired returns the state every scheduled policy should have at t, keyed
+// by policy.
+func (s *PBRScheduler) Desired(t time.Time) map[string]bool {
+	t = t.In(s.location)
+
+	states := make(map[string]bool, len(s.schedules))
+	for _, schedule := range s.schedules {
+		states[schedule.Policy] = schedule.Active(t)
+	}
+
+	return states
+}
+
+// Apply reconciles the policies with their schedule at t in a single commit
+// and returns the policies whose state changed.
+func (s *PBRScheduler) Apply(ctx context.Context, t time.Time) ([]string, error) {
+	refs := make([]string, len(s.schedules))
+	for index, schedule := range s.schedules {
+		refs[index] = schedule.Policy
+	}
+
+	return s.client.setPBRStates(ctx, refs, s.Desired(t))
+}
+
+// Run applies the schedule right away and then at every interval until ctx
+// is done. Failures are logged and retried at the next interval.
+func (s *PBRScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := s.Apply(ctx, time.Now())
+		switch {
+		case err != nil:
+			s.logger.Error("failed to apply pbr schedule", "error", err)
+		case len(changed) > 0:
+			s.logger.Info("applied pbr schedule", "changed", changed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Validate checks the policy and windows of a schedule.
+func (s PBRSchedule) Validate() error {
+	if s.Policy == "" {
+		return fmt.Errorf("policy is required")
+	}
+
+	for _, window := range s.Windows {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("pbr policy %s: %w", s.Policy, err)
+		}
+	}
+
+	return nil
+}
+
+// Active reports whether t is inside any window of the schedule.
+func (s PBRSchedule) Active(t time.Time) bool {
+	for _, window := range s.Windows {
+		if window.Active(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks that the window is either weekly or cron based.
+func (w ScheduleWindow) Validate() error {
+	if w.Cron != "" {
+		if w.Start != "" || w.End != "" || len(w.Days) > 0 {
+			return fmt.Errorf("invalid window: cron windows have no days, start or end")
+		}
+
+		if w.Duration < time.Minute {
+			return fmt.Errorf("invalid duration: %s, expected at least a minute", w.Duration)
+		}
+
+		_, err := parseCron(w.Cron)
+		return err
+	}
+
+	start, err := parseTimeOfDay("start", w.Start)
+	if err != nil {
+		return err
+	}
+
+	end, err := parseTimeOfDay("end", w.End)
+	if err != nil {
+		return err
+	}
+
+	if start == end {
+		return fmt.Errorf("invalid window: start and end are both %s", w.Start)
+	}
+
+	for _, day := range w.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid day: %d", day)
+		}
+	}
+
+	return nil
+}
+
+// Active reports whether t is inside the window, in the time zone of t.
+// Invalid windows are never active.
+func (w ScheduleWindow) Active(t time.Time) bool {
+	if w.Cron != "" {
+		cron, err := parseCron(w.Cron)
+		if err != nil {
+			return false
+		}
+
+		// look for a start within the duration before t
+		minute := t.Truncate(time.Minute)
+		for start := minute; t.Sub(start) < w.Duration; start = start.Add(-time.Minute) {
+			if cron.matches(start) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	start, errStart := parseTimeOfDay("start", w.Start)
+	end, errEnd := parseTimeOfDay("end", w.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start < end {
+		return w.onDay(t.Weekday()) && now >= start && now < end
+	}
+
+	// the window started yesterday or ends tomorrow
+	yesterday := (t.Weekday() + 6) % 7
+	return w.onDay(t.Weekday()) && now >= start || w.onDay(yesterday) && now < end
+}
+
+func (w ScheduleWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	return slices.Contains(w.Days, day)
+}
+
+// parseTimeOfDay parses HH:MM into the time elapsed since midnight.
+func parseTimeOfDay(field, value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s, expected HH:MM", field, value)
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("PBR scheduler", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+		logger *slog.Logger
+	)
+
+	// 2026-10-19 is a monday
+	at := func(day int, clock string) time.Time {
+		parsed, err := time.Parse("15:04", clock)
+		Expect(err).To(BeNil())
+		return time.Date(2026, 10, day, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+	}
+
+	schoolNights := ScheduleWindow{
+		Days:  []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		Start: "20:00",
+		End:   "07:00",
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		router = fakerouter.New("root", "password")
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "kids", "enabled": "0", "interface": "wg0"})
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "backup", "interface": "wan"})
+	})
+
+	It("compute weekly windows", func() {
+		Expect(schoolNights.Active(at(19, "21:00"))).To(BeTrue())
+		Expect(schoolNights.Active(at(20, "06:59"))).To(BeTrue())
+		Expect(schoolNights.Active(at(20, "07:00"))).To(BeFalse())
+		Expect(schoolNights.Active(at(23, "21:00"))).To(BeFalse())
+		Expect(schoolNights.Active(at(24, "06:00"))).To(BeFalse())
+		Expect(schoolNights.Active(at(25, "23:00"))).To(BeTrue())
+
+		daily := ScheduleWindow{Start: "09:00", End: "17:30"}
+		Expect(daily.Active(at(24, "17:29"))).To(BeTrue())
+		Expect(daily.Active(at(24, "17:30"))).To(BeFalse())
+	})
+
+	It("compute cron windows", func() {
+		nightly := ScheduleWindow{Cron: "30 1 * * 1-5", Duration: 2 * time.Hour}
+		Expect(nightly.Active(at(19, "01:29"))).To(BeFalse())
+		Expect(nightly.Active(at(19, "01:30"))).To(BeTrue())
+		Expect(nightly.Active(at(19, "03:29"))).To(BeTrue())
+		Expect(nightly.Active(at(19, "03:30"))).To(BeFalse())
+		Expect(nightly.Active(at(25, "02:00"))).To(BeFalse())
+
+		quarters := ScheduleWindow{Cron: "*/15 * 1,15 * *", Duration: 5 * time.Minute}
+		Expect(quarters.Active(time.Date(2026, 11, 15, 10, 47, 0, 0, time.UTC))).To(BeTrue())
+		Expect(quarters.Active(time.Date(2026, 11, 15, 10, 50, 0, 0, time.UTC))).To(BeFalse())
+		Expect(quarters.Active(time.Date(2026, 11, 16, 10, 47, 0, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("apply the schedule in one commit", func() {
+		scheduler, err := NewPBRScheduler(newFakeOpenWRT(router), []PBRSchedule{
+			{Policy: "kids", Windows: []ScheduleWindow{schoolNights}},
+			{Policy: "backup", Windows: []ScheduleWindow{{Cron: "0 3 * * *", Duration: time.Hour}}},
+		}, logger)
+		Expect(err).To(BeNil())
+		scheduler.SetLocation(time.UTC)
+
+		Expect(scheduler.Desired(at(19, "21:00"))).To(Equal(map[string]bool{"kids": true, "backup": false}))
+
+		changed, err := scheduler.Apply(ctx, at(19, "21:00"))
+		Expect(err).To(BeNil())
+		Expect(changed).To(Equal([]string{"kids", "backup"}))
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		changed, err = scheduler.Apply(ctx, at(19, "22:00"))
+		Expect(err).To(BeNil())
+		Expect(changed).To(BeEmpty())
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		changed, err = scheduler.Apply(ctx, at(20, "03:15"))
+		Expect(err).To(BeNil())
+		Expect(changed).To(Equal([]string{"backup"}))
+		Expect(router.Sections("pbr", "policy")).To(HaveEach(HaveKeyWithValue("enabled", "1")))
+	})
+
+	It("run until the context is done", func() {
+		scheduler, err := NewPBRScheduler(newFakeOpenWRT(router), []PBRSchedule{
+			{Policy: "kids", Windows: []ScheduleWindow{{Start: "00:00", End: "23:59"}, {Start: "23:59", End: "00:00"}}},
+		}, logger)
+		Expect(err).To(BeNil())
+		scheduler.SetInterval(10 * time.Millisecond)
+
+		runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		Expect(scheduler.Run(runCtx)).To(MatchError(context.DeadlineExceeded))
+		Expect(router.Sections("pbr", "policy")).To(ContainElement(And(
+			HaveKeyWithValue("name", "kids"),
+			HaveKeyWithValue("enabled", "1"),
+		)))
+		Expect(router.Commits("pbr")).To(Equal(1))
+	})
+
+	It("validate", func() {
+		invalid := []PBRSchedule{
+			{Windows: []ScheduleWindow{schoolNights}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Start: "25:00", End: "07:00"}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Start: "07:00", End: "07:00"}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Start: "07:00", End: "08:00", Days: []time.Weekday{9}}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Cron: "0 3 * *", Duration: time.Hour}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Cron: "0 24 * * *", Duration: time.Hour}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Cron: "0 3 * * *"}}},
+			{Policy: "kids", Windows: []ScheduleWindow{{Cron: "0 3 * * *", Duration: time.Hour, Start: "03:00"}}},
+		}
+		for _, schedule := range invalid {
+			Expect(schedule.Validate()).ToNot(Succeed(), "%+v", schedule)
+		}
+
+		_, err := NewPBRScheduler(nil, []PBRSchedule{{Policy: "kids"}, {Policy: "kids"}}, logger)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,171 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("PBR policies", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "wan", map[string]any{"proto": "dhcp"})
+		router.AddSection("network", "interface", "wg0", map[string]any{"proto": "wireguard"})
+		router.AddSection("pbr", "policy", "", map[string]any{
+			"name":      "vpn",
+			"src_addr":  "192.168.1.10",
+			"interface": "wg0",
+		})
+	})
+
+	It("create, update, rename and delete a policy", func() {
+		o := newFakeOpenWRT(router)
+		policy := PBR{
+			Name:      "streaming",
+			SrcAddr:   "192.168.1.0/24 !192.168.1.1",
+			DsrAddr:   "example.com",
+			DestPort:  "80,443,8000-8080",
+			Proto:     "tcp",
+			Interface: "wan",
+			Enabled:   "1",
+		}
+		Expect(o.SetPBRPolicies(ctx, []PBR{policy})).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ContainElement(And(
+			HaveKeyWithValue("name", "streaming"),
+			HaveKeyWithValue("dest_port", "80,443,8000-8080"),
+			HaveKeyWithValue("proto", "tcp"),
+		)))
+
+		err := o.SetPBRPolicies(ctx, []PBR{policy})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		policy.Proto = ""
+		policy.Chain = "forward"
+		Expect(o.UpdatePBRPolicies(ctx, []PBR{policy})).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ContainElement(And(
+			HaveKeyWithValue("name", "streaming"),
+			HaveKeyWithValue("chain", "forward"),
+			Not(HaveKey("proto")),
+		)))
+
+		Expect(o.RenamePBRPolicy(ctx, "streaming", "media")).To(Succeed())
+		err = o.RenamePBRPolicy(ctx, "media", "vpn")
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		err = o.RenamePBRPolicy(ctx, "streaming", "tv")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+
+		Expect(o.DeletePBRPolicies(ctx, []string{"media"})).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ConsistOf(HaveKeyWithValue("name", "vpn")))
+		Expect(o.DeletePBRPolicies(ctx, []string{"media"})).To(MatchError("pbr policies not found: [media]"))
+		Expect(router.Commits("pbr")).To(Equal(4))
+	})
+
+	It("enable and disable policies in one commit", func() {
+		wifi := router.AddSection("pbr", "policy", "", map[string]any{"name": "wifi", "enabled": "0", "interface": "wan"})
+		o := newFakeOpenWRT(router)
+
+		changed, err := o.EnablePBRPolicies(ctx, []string{"vpn", wifi}, true)
+		Expect(err).To(BeNil())
+		Expect(changed).To(Equal([]string{wifi}))
+		Expect(router.Sections("pbr", "policy")).To(ContainElement(And(
+			HaveKeyWithValue("name", "wifi"),
+			HaveKeyWithValue("enabled", "1"),
+		)))
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		changed, err = o.EnablePBRPolicies(ctx, []string{"vpn", "wifi"}, false)
+		Expect(err).To(BeNil())
+		Expect(changed).To(Equal([]string{"vpn", "wifi"}))
+		Expect(router.Sections("pbr", "policy")).To(HaveEach(HaveKeyWithValue("enabled", "0")))
+		Expect(router.Commits("pbr")).To(Equal(2))
+
+		changed, err = o.EnablePBRPolicies(ctx, []string{"vpn"}, false)
+		Expect(err).To(BeNil())
+		Expect(changed).To(BeEmpty())
+		Expect(router.Commits("pbr")).To(Equal(2))
+	})
+
+	It("report unknown and ambiguous policies", func() {
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "vpn", "interface": "wan"})
+		o := newFakeOpenWRT(router)
+
+		err := o.EnablePBRPolicy(ctx, "vnp", true)
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		Expect(err).To(MatchError("section not found: pbr policies [vnp]"))
+
+		err = o.EnablePBRPolicy(ctx, "vpn", false)
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		Expect(err).To(MatchError("ambiguous name: pbr policy vpn matches sections [cfg1 cfg2]"))
+		Expect(router.Commits("pbr")).To(Equal(0))
+
+		Expect(o.EnablePBRPolicy(ctx, "cfg2", false)).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ConsistOf(
+			Not(HaveKey("enabled")),
+			HaveKeyWithValue("enabled", "0"),
+		))
+	})
+
+	It("address duplicate names by section ID", func() {
+		router.AddSection("pbr", "policy", "", map[string]any{"name": "vpn", "src_addr": "192.168.1.11", "interface": "wan"})
+		o := newFakeOpenWRT(router)
+
+		err := o.UpdatePBRPolicies(ctx, []PBR{{Name: "vpn", SrcAddr: "192.168.1.12", Interface: "wan"}})
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		err = o.RenamePBRPolicy(ctx, "vpn", "tv")
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		err = o.DeletePBRPolicies(ctx, []string{"vpn"})
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		Expect(router.Commits("pbr")).To(Equal(0))
+
+		Expect(o.UpdatePBRPolicies(ctx, []PBR{{Name: "cfg2", SrcAddr: "192.168.1.12", Interface: "wan"}})).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ContainElement(And(
+			HaveKeyWithValue(".name", "cfg2"),
+			HaveKeyWithValue("name", "vpn"),
+			HaveKeyWithValue("src_addr", "192.168.1.12"),
+		)))
+
+		Expect(o.RenamePBRPolicy(ctx, "cfg2", "tv")).To(Succeed())
+		Expect(o.DeletePBRPolicies(ctx, []string{"vpn"})).To(Succeed())
+		Expect(router.Sections("pbr", "policy")).To(ConsistOf(HaveKeyWithValue("name", "tv")))
+		Expect(router.Commits("pbr")).To(Equal(3))
+	})
+
+	It("validate the interface", func() {
+		o := newFakeOpenWRT(router)
+		err := o.SetPBRPolicies(ctx, []PBR{{Name: "lan", SrcAddr: "192.168.1.20", Interface: "lan"}})
+		Expect(err).To(MatchError("invalid interface: lan, not a network interface"))
+		Expect(router.Commits("pbr")).To(Equal(0))
+	})
+
+	It("validate", func() {
+		valid := PBR{Name: "vpn", SrcAddr: "aa:bb:cc:dd:ee:01", Interface: "wg0"}
+		Expect(valid.Validate()).To(Succeed())
+		Expect(PBR{Name: "vpn", DsrAddr: "!@pbr_vpn_dst", Interface: "wg0"}.Validate()).To(Succeed())
+
+		invalid := []PBR{
+			{SrcAddr: "192.168.1.10", Interface: "wg0"},
+			{Name: "vpn", SrcAddr: "192.168.1.10"},
+			{Name: "vpn", Interface: "wg0"},
+			{Name: "vpn", SrcAddr: "192.168.1.300", Interface: "wg0"},
+			{Name: "vpn", DsrAddr: "aa:bb:cc:dd:ee:01", Interface: "wg0"},
+			{Name: "vpn", DsrAddr: "@pbr-vpn", Interface: "wg0"},
+			{Name: "vpn", DestPort: "0", Interface: "wg0"},
+			{Name: "vpn", DestPort: "443-80", Interface: "wg0"},
+			{Name: "vpn", DestPort: "443", Proto: "sctp", Interface: "wg0"},
+			{Name: "vpn", DestPort: "443", Chain: "nat", Interface: "wg0"},
+			{Name: "vpn", DestPort: "443", Enabled: "yes", Interface: "wg0"},
+		}
+		for _, policy := range invalid {
+			Expect(policy.Validate()).ToNot(Succeed(), "%+v", policy)
+		}
+	})
+})
@@ -0,0 +1,310 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var routeTypes = []string{"unicast", "local", "broadcast", "multicast", "unreachable", "prohibit", "blackhole", "anycast"}
+
+// GetRoutes retrieves the static IPv4 and IPv6 routes from the OpenWRT
+// device, keyed by section name.
+func (o *OpenWRT) GetRoutes(ctx context.Context) (map[string]Route, error) {
+	return getSections[Route](ctx, o, "network", "route", "route6")
+}
+
+// SetRoutes adds static routes to the OpenWRT device, then commits and
+// reloads the network. Nothing is written when a route is invalid or the
+// same target is already routed in its table.
+func (o *OpenWRT) SetRoutes(ctx context.Context, routes []Route) error {
+	currentRoutes, err := o.GetRoutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, route := range routes {
+		if err := o.validateRoute(ctx, route); err != nil {
+			return err
+		}
+
+		if _, ok := findRoute(currentRoutes, route); ok {
+			return fmt.Errorf("%w: route to %s already exists in table %s", ErrConflict, route.Target, route.table())
+		}
+		currentRoutes[fmt.Sprintf("new route %d", index)] = route
+	}
+
+	for _, route := range routes {
+		if _, err := o.addSection(ctx, "network", route.Type, route); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateRoutes updates existing static routes on the OpenWRT device, matched
+// by family, target and table, then commits and reloads the network. Empty
+// fields are removed from the route.
+func (o *OpenWRT) UpdateRoutes(ctx context.Context, updateRoutes []Route) error {
+	currentRoutes, err := o.GetRoutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateRoutes))
+	var notFound []string
+	for index, route := range updateRoutes {
+		if err := o.validateRoute(ctx, route); err != nil {
+			return err
+		}
+
+		cfg, ok := findRoute(currentRoutes, route)
+		if !ok {
+			notFound = append(notFound, route.Target)
+			continue
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("routes not found: %v", notFound)
+	}
+
+	for index, route := range updateRoutes {
+		if err := o.updateSection(ctx, "network", cfgs[index], route); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteRoutes deletes static routes from the OpenWRT device, matched by
+// family, target and table, then commits and reloads the network.
+func (o *OpenWRT) DeleteRoutes(ctx context.Context, deleteRoutes []Route) error {
+	currentRoutes, err := o.GetRoutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, route := range deleteRoutes {
+		cfg, ok := findRoute(currentRoutes, route)
+		if !ok {
+			notFound = append(notFound, route.Target)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("routes not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// FindRouteOverlaps returns the static routes of the OpenWRT device whose
+// targets overlap within the same table.
+func (o *OpenWRT) FindRouteOverlaps(ctx context.Context) ([]RouteOverlap, error) {
+	routes, err := o.GetRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return RouteOverlaps(routes), nil
+}
+
+// RouteOverlaps returns the pairs of routes, keyed by section name, whose
+// targets overlap within the same table, sorted by section name. Routes with
+// an invalid target are ignored.
+func RouteOverlaps(routes map[string]Route) []RouteOverlap {
+	cfgs := make([]string, 0, len(routes))
+	for cfg := range routes {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	var overlaps []RouteOverlap
+	for index, cfg := range cfgs {
+		prefix, err := routes[cfg].prefix()
+		if err != nil {
+			continue
+		}
+
+		for _, other := range cfgs[index+1:] {
+			otherPrefix, err := routes[other].prefix()
+			if err != nil || routes[cfg].table() != routes[other].table() || !prefix.Overlaps(otherPrefix) {
+				continue
+			}
+
+			overlaps = append(overlaps, RouteOverlap{
+				Table:    routes[cfg].table(),
+				Sections: []string{cfg, other},
+				Targets:  []string{prefix.String(), otherPrefix.String()},
+			})
+		}
+	}
+
+	return overlaps
+}
+
+// Validate checks the fields of a static route.
+func (r Route) Validate() error {
+	if r.Type != "route" && r.Type != "route6" {
+		return fmt.Errorf("invalid type: %s, expected route or route6", r.Type)
+	}
+
+	if r.Interface == "" {
+		return fmt.Errorf("interface is required")
+	}
+
+	if r.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+
+	if r.Type == "route6" && r.Netmask != "" {
+		return fmt.Errorf("invalid netmask: %s, route6 targets are in CIDR notation", r.Netmask)
+	}
+
+	if strings.Contains(r.Target, "/") && r.Netmask != "" {
+		return fmt.Errorf("invalid target: %s, netmask is set", r.Target)
+	}
+
+	if _, err := r.prefix(); err != nil {
+		return err
+	}
+
+	if r.Gateway != "" {
+		gateway, err := netip.ParseAddr(r.Gateway)
+		if err != nil || gateway.Is6() != (r.Type == "route6") {
+			return fmt.Errorf("invalid gateway: %s", r.Gateway)
+		}
+	}
+
+	if err := validateUint("metric", r.Metric, 0, 1<<32-1); err != nil {
+		return err
+	}
+
+	if err := validateRouteTable("table", r.Table); err != nil {
+		return err
+	}
+
+	if r.RouteType != "" && !slices.Contains(routeTypes, r.RouteType) {
+		return fmt.Errorf("invalid type: %s, expected %s", r.RouteType, strings.Join(routeTypes, ", "))
+	}
+
+	return nil
+}
+
+// validateRoute checks the route and that its interface exists on the device.
+func (o *OpenWRT) validateRoute(ctx context.Context, route Route) error {
+	if err := route.Validate(); err != nil {
+		return err
+	}
+
+	return o.validateInterfaceNames(ctx, route.Interface)
+}
+
+// validateInterfaceNames checks that the interfaces exist on the device,
+// ignoring empty names.
+func (o *OpenWRT) validateInterfaceNames(ctx context.Context, names ...string) error {
+	interfaces, err := o.GetInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checkInterfaceNames(interfaces, names...)
+}
+
+// checkInterfaceNames checks that the interfaces are in interfaces, skipping
+// empty names.
+func checkInterfaceNames(interfaces map[string]Interface, names ...string) error {
+	for _, name := range names {
+		if _, ok := interfaces[name]; name != "" && !ok {
+			return fmt.Errorf("invalid interface: %s, not a network interface", name)
+		}
+	}
+
+	return nil
+}
+
+// prefix returns the target subnet of the route.
+func (r Route) prefix() (netip.Prefix, error) {
+	if r.Type == "route6" {
+		prefix, err := netip.ParsePrefix(r.Target)
+		if err != nil {
+			addr, errAddr := netip.ParseAddr(r.Target)
+			if errAddr != nil {
+				return netip.Prefix{}, fmt.Errorf("invalid target: %s", r.Target)
+			}
+			prefix = netip.PrefixFrom(addr, 128)
+		}
+
+		if !prefix.Addr().Is6() {
+			return netip.Prefix{}, fmt.Errorf("invalid target: %s, expected an IPv6 subnet", r.Target)
+		}
+
+		return prefix.Masked(), nil
+	}
+
+	prefix, err := parseIPv4Prefix(r.Target, r.Netmask)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid target: %s, expected an IPv4 subnet", r.Target)
+	}
+
+	return prefix, nil
+}
+
+func (r Route) table() string {
+	if r.Table == "" {
+		return "main"
+	}
+
+	return r.Table
+}
+
+// validateRouteTable checks a routing table given by number or by name.
+func validateRouteTable(field, table string) error {
+	if table == "" {
+		return nil
+	}
+
+	if _, err := strconv.ParseUint(table, 10, 32); err == nil {
+		return nil
+	}
+
+	return validateUciName(field, table)
+}
+
+func findRoute(routes map[string]Route, route Route) (string, bool) {
+	prefix, err := route.prefix()
+	if err != nil {
+		return "", false
+	}
+
+	for cfg, current := range routes {
+		currentPrefix, err := current.prefix()
+		if err == nil && current.Type == route.Type && currentPrefix == prefix && current.table() == route.table() {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
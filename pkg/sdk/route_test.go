@@ -0,0 +1,151 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("Routes and rules", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "lan", map[string]any{"proto": "static", "ipaddr": "192.168.1.1/24"})
+		router.AddSection("network", "interface", "wan", map[string]any{"proto": "dhcp"})
+		router.AddSection("network", "route", "", map[string]any{
+			"interface": "lan",
+			"target":    "10.0.0.0",
+			"netmask":   "255.0.0.0",
+			"gateway":   "192.168.1.254",
+		})
+		router.AddSection("network", "rule", "", map[string]any{"src": "192.168.1.0/24", "lookup": "100", "priority": "1000"})
+	})
+
+	It("create, update and delete a route", func() {
+		o := newFakeOpenWRT(router)
+		route := Route{Type: "route6", Interface: "wan", Target: "2001:db8::/32", Gateway: "fe80::1", Metric: "10"}
+		Expect(o.SetRoutes(ctx, []Route{route})).To(Succeed())
+		Expect(router.Sections("network", "route6")).To(ConsistOf(And(
+			HaveKeyWithValue("target", "2001:db8::/32"),
+			HaveKeyWithValue("metric", "10"),
+		)))
+
+		err := o.SetRoutes(ctx, []Route{{Type: "route", Interface: "wan", Target: "10.0.0.0/8"}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		err = o.SetRoutes(ctx, []Route{{Type: "route", Interface: "guest", Target: "172.16.0.0/12"}})
+		Expect(err).To(MatchError("invalid interface: guest, not a network interface"))
+
+		route.Metric = ""
+		route.Table = "100"
+		Expect(o.UpdateRoutes(ctx, []Route{route})).To(MatchError("routes not found: [2001:db8::/32]"))
+		route.Table = ""
+		route.RouteType = "blackhole"
+		Expect(o.UpdateRoutes(ctx, []Route{route})).To(Succeed())
+		Expect(router.Sections("network", "route6")).To(ConsistOf(And(
+			HaveKeyWithValue("type", "blackhole"),
+			Not(HaveKey("metric")),
+		)))
+
+		Expect(o.DeleteRoutes(ctx, []Route{route, {Type: "route", Target: "10.0.0.0/8"}})).To(Succeed())
+		Expect(router.Sections("network", "route6")).To(BeEmpty())
+		Expect(router.Sections("network", "route")).To(BeEmpty())
+		Expect(router.Commits("network")).To(Equal(3))
+		Expect(router.History()).To(HaveLen(3))
+	})
+
+	It("detect overlapping routes in the same table", func() {
+		router.AddSection("network", "route", "", map[string]any{"interface": "lan", "target": "10.1.0.0/16"})
+		router.AddSection("network", "route", "", map[string]any{"interface": "lan", "target": "10.2.0.0/16", "table": "100"})
+		router.AddSection("network", "route", "", map[string]any{"interface": "lan", "target": "172.16.0.0/12"})
+		o := newFakeOpenWRT(router)
+		Expect(o.FindRouteOverlaps(ctx)).To(Equal([]RouteOverlap{
+			{Table: "main", Sections: []string{"cfg1", "cfg3"}, Targets: []string{"10.0.0.0/8", "10.1.0.0/16"}},
+		}))
+	})
+
+	It("create, update and delete a rule", func() {
+		o := newFakeOpenWRT(router)
+		rule := Rule{Type: "rule", In: "lan", Mark: "0x10/0xff", Lookup: "wan_table", Priority: "900"}
+		Expect(o.SetRules(ctx, []Rule{rule})).To(Succeed())
+		Expect(o.GetRules(ctx)).To(HaveLen(2))
+
+		err := o.SetRules(ctx, []Rule{{Type: "rule", Dest: "8.8.8.8", Lookup: "main", Priority: "1000"}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		rule.Invert = "1"
+		Expect(o.UpdateRules(ctx, []Rule{rule})).To(Succeed())
+		Expect(router.Sections("network", "rule")).To(ContainElement(And(
+			HaveKeyWithValue("priority", "900"),
+			HaveKeyWithValue("invert", "1"),
+		)))
+
+		Expect(o.DeleteRules(ctx, []Rule{{Type: "rule", Priority: "900"}})).To(Succeed())
+		Expect(router.Sections("network", "rule")).To(HaveLen(1))
+		Expect(o.DeleteRules(ctx, []Rule{{Type: "rule6", Priority: "1000"}})).To(MatchError("rules not found: [rule6 1000]"))
+	})
+
+	It("address rules without a priority by section", func() {
+		router.AddSection("network", "rule", "", map[string]any{"in": "lan", "lookup": "100"})
+		o := newFakeOpenWRT(router)
+		Expect(o.SetRules(ctx, []Rule{{Type: "rule", Out: "wan", Lookup: "main"}})).To(Succeed())
+		rules, err := o.GetRules(ctx)
+		Expect(err).To(BeNil())
+		Expect(rules).To(HaveKeyWithValue("cfg4", Rule{Type: "rule", Section: "cfg4", Out: "wan", Lookup: "main"}))
+
+		err = o.UpdateRules(ctx, []Rule{{Type: "rule", In: "lan", Lookup: "200"}})
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		Expect(err).To(MatchError("ambiguous name: rule without priority matches sections [cfg3 cfg4]"))
+		err = o.DeleteRules(ctx, []Rule{{Type: "rule"}})
+		Expect(errors.Is(err, ErrAmbiguous)).To(BeTrue())
+		Expect(router.Commits("network")).To(Equal(1))
+
+		Expect(o.UpdateRules(ctx, []Rule{{Type: "rule", Section: "cfg3", In: "lan", Lookup: "200"}})).To(Succeed())
+		Expect(router.Sections("network", "rule")).To(ContainElement(And(
+			HaveKeyWithValue(".name", "cfg3"),
+			HaveKeyWithValue("lookup", "200"),
+		)))
+
+		Expect(o.DeleteRules(ctx, []Rule{{Type: "rule", Section: "cfg4"}})).To(Succeed())
+		Expect(router.Sections("network", "rule")).To(HaveLen(2))
+		Expect(o.DeleteRules(ctx, []Rule{{Type: "rule6", Section: "cfg3"}})).To(MatchError("rules not found: [cfg3]"))
+	})
+
+	It("validate", func() {
+		invalidRoutes := []Route{
+			{Type: "route", Target: "10.0.0.0/8"},
+			{Type: "route", Interface: "lan"},
+			{Type: "route", Interface: "lan", Target: "10.0.0.0/33"},
+			{Type: "route", Interface: "lan", Target: "10.0.0.0/8", Netmask: "255.0.0.0"},
+			{Type: "route", Interface: "lan", Target: "2001:db8::/32"},
+			{Type: "route", Interface: "lan", Target: "10.0.0.0/8", Gateway: "fe80::1"},
+			{Type: "route6", Interface: "lan", Target: "2001:db8::/32", Netmask: "255.0.0.0"},
+			{Type: "route", Interface: "lan", Target: "10.0.0.0/8", Table: "my table"},
+			{Type: "route", Interface: "lan", Target: "10.0.0.0/8", RouteType: "nat"},
+		}
+		for _, route := range invalidRoutes {
+			Expect(route.Validate()).ToNot(Succeed(), "%+v", route)
+		}
+
+		Expect(Rule{Type: "rule", Lookup: "100"}.Validate()).To(Succeed())
+		invalidRules := []Rule{
+			{Type: "rule", Lookup: "100", Priority: "high"},
+			{Type: "rule", Priority: "10"},
+			{Type: "rule6", Src: "192.168.1.0/24", Lookup: "100", Priority: "10"},
+			{Type: "rule", Dest: "example.com", Lookup: "100", Priority: "10"},
+			{Type: "rule", Mark: "0x10/xyz", Lookup: "100", Priority: "10"},
+			{Type: "route", Lookup: "100", Priority: "10"},
+		}
+		for _, rule := range invalidRules {
+			Expect(rule.Validate()).ToNot(Succeed(), "%+v", rule)
+		}
+	})
+})
@@ -0,0 +1,240 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GetRules retrieves the IPv4 and IPv6 routing policy rules from the OpenWRT
+// device, keyed by section name.
+func (o *OpenWRT) GetRules(ctx context.Context) (map[string]Rule, error) {
+	return getSections[Rule](ctx, o, "network", "rule", "rule6")
+}
+
+// SetRules adds routing policy rules to the OpenWRT device, then commits and
+// reloads the network. Nothing is written when a rule is invalid or its
+// priority is already used by a rule of the same family.
+func (o *OpenWRT) SetRules(ctx context.Context, rules []Rule) error {
+	currentRules, err := o.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, rule := range rules {
+		if err := o.validateRule(ctx, rule); err != nil {
+			return err
+		}
+
+		for _, current := range currentRules {
+			if rule.Priority != "" && current.Type == rule.Type && current.Priority == rule.Priority {
+				return fmt.Errorf("%w: %s with priority %s already exists", ErrConflict, rule.Type, rule.Priority)
+			}
+		}
+		currentRules[fmt.Sprintf("new rule %d", index)] = rule
+	}
+
+	for _, rule := range rules {
+		if _, err := o.addSection(ctx, "network", rule.Type, rule); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateRules updates existing routing policy rules on the OpenWRT device,
+// matched by section or else by family and priority, then commits and
+// reloads the network. Empty fields are removed from the rule.
+func (o *OpenWRT) UpdateRules(ctx context.Context, updateRules []Rule) error {
+	currentRules, err := o.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateRules))
+	var notFound []string
+	for index, rule := range updateRules {
+		if err := o.validateRule(ctx, rule); err != nil {
+			return err
+		}
+
+		cfg, err := resolveRule(currentRules, rule)
+		if errors.Is(err, ErrSectionNotFound) {
+			notFound = append(notFound, rule.label())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		cfgs[index] = cfg
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("rules not found: %v", notFound)
+	}
+
+	for index, rule := range updateRules {
+		if err := o.updateSection(ctx, "network", cfgs[index], rule); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteRules deletes routing policy rules from the OpenWRT device, matched
+// by section or else by family and priority, then commits and reloads the
+// network.
+func (o *OpenWRT) DeleteRules(ctx context.Context, deleteRules []Rule) error {
+	currentRules, err := o.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, rule := range deleteRules {
+		cfg, err := resolveRule(currentRules, rule)
+		if errors.Is(err, ErrSectionNotFound) {
+			notFound = append(notFound, rule.label())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("rules not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// Validate checks the fields of a routing policy rule. The priority is
+// optional: the kernel orders a rule without one after the existing rules.
+func (r Rule) Validate() error {
+	if r.Type != "rule" && r.Type != "rule6" {
+		return fmt.Errorf("invalid type: %s, expected rule or rule6", r.Type)
+	}
+
+	if err := validateUint("priority", r.Priority, 0, 1<<32-1); err != nil {
+		return err
+	}
+
+	if r.Lookup == "" {
+		return fmt.Errorf("lookup is required")
+	}
+
+	if err := validateRouteTable("lookup", r.Lookup); err != nil {
+		return err
+	}
+
+	for _, subnet := range [][]string{{"src", r.Src}, {"dest", r.Dest}} {
+		if subnet[1] == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(subnet[1])
+		if err != nil {
+			addr, errAddr := netip.ParseAddr(subnet[1])
+			if errAddr != nil {
+				return fmt.Errorf("invalid %s: %s", subnet[0], subnet[1])
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		if prefix.Addr().Is6() != (r.Type == "rule6") {
+			return fmt.Errorf("invalid %s: %s, wrong address family for %s", subnet[0], subnet[1], r.Type)
+		}
+	}
+
+	if r.Mark != "" {
+		if err := validateMark(r.Mark); err != nil {
+			return err
+		}
+	}
+
+	return validateBool("invert", r.Invert)
+}
+
+// validateRule checks the rule and that its interfaces exist on the device.
+func (o *OpenWRT) validateRule(ctx context.Context, rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	return o.validateInterfaceNames(ctx, rule.In, rule.Out)
+}
+
+// validateMark checks a firewall mark with an optional mask, such as 0x10/0xff.
+func validateMark(mark string) error {
+	numbers := strings.SplitN(mark, "/", 2)
+	for _, number := range numbers {
+		if _, err := strconv.ParseUint(number, 0, 32); err != nil {
+			return fmt.Errorf("invalid mark: %s", mark)
+		}
+	}
+
+	return nil
+}
+
+// resolveRule returns the section of the rule with the section of rule or,
+// failing that, the unique rule of its family with its priority.
+func resolveRule(rules map[string]Rule, rule Rule) (string, error) {
+	if rule.Section != "" {
+		if current, ok := rules[rule.Section]; ok && current.Type == rule.Type {
+			return rule.Section, nil
+		}
+
+		return "", fmt.Errorf("%w: %s", ErrSectionNotFound, rule.label())
+	}
+
+	var cfgs []string
+	for cfg, current := range rules {
+		if current.Type == rule.Type && current.Priority == rule.Priority {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	slices.Sort(cfgs)
+
+	switch len(cfgs) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrSectionNotFound, rule.label())
+	case 1:
+		return cfgs[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s matches sections %v", ErrAmbiguous, rule.label(), cfgs)
+	}
+}
+
+// label returns the section of the rule or else its family and priority,
+// identifying it in errors.
+func (r Rule) label() string {
+	if r.Section != "" {
+		return r.Section
+	}
+
+	if r.Priority == "" {
+		return r.Type + " without priority"
+	}
+
+	return r.Type + " " + r.Priority
+}
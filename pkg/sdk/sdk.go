@@ -9,6 +9,8 @@ import (
 //go:generate mockgen -destination=../../internal/mocks/openwrt/lucirpc.go -package=mocks . LuciRPC
 type LuciRPC interface {
 	Uci(context.Context, string, []string) (string, error)
+	UciSetList(context.Context, string, string, string, []string) (string, error)
+	Sys(context.Context, string, []string) (string, error)
 }
 
 // OpenWRT represents an OpenWRT SDK client
@@ -27,3 +29,8 @@ func New(addr, username, password string, rpcID int, insecureSkipVerify bool) (*
 		lucirpc: lrcp,
 	}, nil
 }
+
+// exec runs a shell command on the OpenWRT device and returns its output.
+func (o *OpenWRT) exec(ctx context.Context, command string) (string, error) {
+	return o.lucirpc.Sys(ctx, "exec", []string{command})
+}
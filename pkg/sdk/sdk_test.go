@@ -7,6 +7,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
 	mocks "github.com/renanqts/openwrt-sdk/internal/mocks/openwrt"
 	"go.uber.org/mock/gomock"
 )
@@ -17,6 +18,17 @@ func TestOpenWRT(t *testing.T) {
 	defer GinkgoRecover()
 }
 
+// newFakeOpenWRT returns a client talking to an in-memory router, which is
+// stopped when the spec ends.
+func newFakeOpenWRT(router *fakerouter.Router) *OpenWRT {
+	server := router.Start()
+	DeferCleanup(server.Close)
+
+	o, err := New(server.URL, router.Username, router.Password, 1, false)
+	Expect(err).To(BeNil())
+	return o
+}
+
 var _ = Describe("SDK", func() {
 	var (
 		ctx         context.Context
@@ -391,4 +403,238 @@ var _ = Describe("SDK", func() {
 			Expect(err.Error()).To(Equal("records not found: [{CNAME   whatever 3.3.3.3}]"))
 		})
 	})
+
+	Context("Round-robin DNS", func() {
+		var currentJson []byte
+
+		BeforeEach(func() {
+			var err error
+			currentJson, err = json.Marshal(map[string]DNSRecord{
+				"x": {
+					Type: "domain",
+					Name: "happy.com",
+					IP:   "1.1.1.1",
+				},
+				"y": {
+					Type: "domain",
+					Name: "happy.com",
+					IP:   "2.2.2.2",
+				},
+				"z": {
+					Type:   "cname",
+					CName:  "foo.bar.com",
+					Target: "happy.com",
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("delete a single member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "y"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.DeleteDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "2.2.2.2",
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("delete every member by name", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "x"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "y"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.DeleteDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("change members in one commit", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "y"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "domain"}).Return("w", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "w", "name", "happy.com"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "w", "ip", "3.3.3.3"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ChangeDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "2.2.2.2",
+				},
+			}, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "3.3.3.3",
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("change with an invalid member", func() {
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ChangeDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "2.2.2.2",
+				},
+			}, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+				},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("ip is required"))
+		})
+
+		It("change an unknown member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ChangeDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "4.4.4.4",
+				},
+			}, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "3.3.3.3",
+				},
+			})
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("delete an unknown member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.DeleteDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "3.3.3.3",
+				},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("records not found: [{A 3.3.3.3 happy.com  }]"))
+		})
+
+		It("update the whole group", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "x"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "y"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "domain"}).Return("a", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "a", "name", "happy.com"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "a", "ip", "3.3.3.3"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "domain"}).Return("b", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "b", "name", "happy.com"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "b", "ip", "4.4.4.4"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.UpdateDNSRecords(ctx, []DNSRecord{
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "3.3.3.3",
+				},
+				{
+					Type: "A",
+					Name: "happy.com",
+					IP:   "4.4.4.4",
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("replace a single member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "delete", []string{"dhcp", "x"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "add", []string{"dhcp", "domain"}).Return("a", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "a", "name", "happy.com"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "set", []string{"dhcp", "a", "ip", "3.3.3.3"}).Return("", nil)
+			mockLuciRPC.EXPECT().Uci(ctx, "commit", []string{"dhcp"}).Return("", nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ReplaceDNSRecords(ctx, []DNSRecordChange{
+				{
+					Old: DNSRecord{Type: "A", Name: "happy.com", IP: "1.1.1.1"},
+					New: DNSRecord{Type: "A", Name: "happy.com", IP: "3.3.3.3"},
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("replace an ambiguous member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ReplaceDNSRecords(ctx, []DNSRecordChange{
+				{
+					Old: DNSRecord{Type: "A", Name: "happy.com"},
+					New: DNSRecord{Type: "A", Name: "happy.com", IP: "3.3.3.3"},
+				},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("record matches 2 entries: {A  happy.com  }"))
+		})
+
+		It("replace with an invalid member", func() {
+			mockLuciRPC.EXPECT().Uci(ctx, "get_all", []string{"dhcp"}).Return(string(currentJson), nil)
+
+			o := OpenWRT{
+				lucirpc: mockLuciRPC,
+			}
+			err := o.ReplaceDNSRecords(ctx, []DNSRecordChange{
+				{
+					Old: DNSRecord{Type: "A", Name: "happy.com", IP: "1.1.1.1"},
+					New: DNSRecord{Type: "A", Name: "happy.com", IP: "3.3.3.3"},
+				},
+				{
+					Old: DNSRecord{Type: "A", Name: "happy.com", IP: "2.2.2.2"},
+					New: DNSRecord{Type: "A", Name: "happy.com", IP: "fd00::3"},
+				},
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("invalid ip for A record: fd00::3"))
+		})
+	})
 })
@@ -0,0 +1,247 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// networkSwitch represents a swconfig switch section
+type networkSwitch struct {
+	Type string `json:".type"`
+	Name string `json:"name"`
+}
+
+// GetSwitchVLANs retrieves the VLANs of the swconfig switches from the
+// OpenWRT device, keyed by section name.
+func (o *OpenWRT) GetSwitchVLANs(ctx context.Context) (map[string]SwitchVLAN, error) {
+	return getSections[SwitchVLAN](ctx, o, "network", "switch_vlan")
+}
+
+// SetSwitchVLANs adds VLANs to the switches of a swconfig router, then
+// commits and reloads the network. Nothing is written when a VLAN is
+// invalid or its number or VID is already used on its switch.
+func (o *OpenWRT) SetSwitchVLANs(ctx context.Context, vlans []SwitchVLAN) error {
+	currentVLANs, err := o.GetSwitchVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	switches, err := o.getSwitches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, vlan := range vlans {
+		if err := validateSwitchVLAN(switches, vlan); err != nil {
+			return err
+		}
+
+		if _, ok := findSwitchVLAN(currentVLANs, vlan); ok {
+			return fmt.Errorf("%w: vlan %s already exists on switch %s", ErrConflict, vlan.VLAN, vlan.Device)
+		}
+		currentVLANs[fmt.Sprintf("new vlan %d", index)] = vlan
+	}
+
+	if err := checkSwitchVIDs(currentVLANs); err != nil {
+		return err
+	}
+
+	for _, vlan := range vlans {
+		if _, err := o.addSection(ctx, "network", "switch_vlan", vlan); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateSwitchVLANs updates existing switch VLANs, matched by switch and VLAN
+// number, then commits and reloads the network. Empty fields are removed
+// from the VLAN. Nothing is written when a VLAN is invalid or its VID is
+// already used on its switch.
+func (o *OpenWRT) UpdateSwitchVLANs(ctx context.Context, updateVLANs []SwitchVLAN) error {
+	currentVLANs, err := o.GetSwitchVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	switches, err := o.getSwitches(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updateVLANs))
+	var notFound []string
+	for index, vlan := range updateVLANs {
+		if err := validateSwitchVLAN(switches, vlan); err != nil {
+			return err
+		}
+
+		cfg, ok := findSwitchVLAN(currentVLANs, vlan)
+		if !ok {
+			notFound = append(notFound, vlan.Device+"."+vlan.VLAN)
+			continue
+		}
+		cfgs[index] = cfg
+		currentVLANs[cfg] = vlan
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("switch vlans not found: %v", notFound)
+	}
+
+	if err := checkSwitchVIDs(currentVLANs); err != nil {
+		return err
+	}
+
+	for index, vlan := range updateVLANs {
+		if err := o.updateSection(ctx, "network", cfgs[index], vlan); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteSwitchVLANs deletes switch VLANs, matched by switch and VLAN number,
+// then commits and reloads the network.
+func (o *OpenWRT) DeleteSwitchVLANs(ctx context.Context, deleteVLANs []SwitchVLAN) error {
+	currentVLANs, err := o.GetSwitchVLANs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, vlan := range deleteVLANs {
+		cfg, ok := findSwitchVLAN(currentVLANs, vlan)
+		if !ok {
+			notFound = append(notFound, vlan.Device+"."+vlan.VLAN)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("switch vlans not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// Validate checks the fields of a switch VLAN.
+func (v SwitchVLAN) Validate() error {
+	if err := validateDeviceName("device", v.Device); err != nil {
+		return err
+	}
+
+	if v.VLAN == "" {
+		return fmt.Errorf("vlan is required")
+	}
+
+	if err := validateUint("vlan", v.VLAN, 0, 4094); err != nil {
+		return err
+	}
+
+	if err := validateUint("vid", v.VID, 1, 4094); err != nil {
+		return err
+	}
+
+	ports := strings.Fields(v.Ports)
+	if len(ports) == 0 {
+		return fmt.Errorf("ports is required")
+	}
+
+	var numbers []string
+	for _, port := range ports {
+		number := strings.TrimSuffix(port, "t")
+		if _, err := strconv.ParseUint(number, 10, 8); err != nil {
+			return fmt.Errorf("invalid ports: %s, expected port numbers with an optional t suffix", port)
+		}
+
+		if slices.Contains(numbers, number) {
+			return fmt.Errorf("invalid ports: port %s is listed twice", number)
+		}
+		numbers = append(numbers, number)
+	}
+
+	return nil
+}
+
+// vid returns the VLAN ID the switch tags the VLAN with.
+func (v SwitchVLAN) vid() string {
+	if v.VID == "" {
+		return v.VLAN
+	}
+
+	return v.VID
+}
+
+func (o *OpenWRT) getSwitches(ctx context.Context) (map[string]networkSwitch, error) {
+	return getSections[networkSwitch](ctx, o, "network", "switch")
+}
+
+// validateSwitchVLAN checks the VLAN and that its device is a switch.
+func validateSwitchVLAN(switches map[string]networkSwitch, vlan SwitchVLAN) error {
+	if err := vlan.Validate(); err != nil {
+		return err
+	}
+
+	if len(switches) == 0 {
+		return fmt.Errorf("switch vlans are not supported on %s routers", VLANModelDSA)
+	}
+
+	for _, sw := range switches {
+		if sw.Name == vlan.Device {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid device: %s, not a switch", vlan.Device)
+}
+
+// checkSwitchVIDs checks that every VID is used by at most one VLAN of its
+// switch.
+func checkSwitchVIDs(vlans map[string]SwitchVLAN) error {
+	cfgs := make([]string, 0, len(vlans))
+	for cfg := range vlans {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	numbers := make(map[string]string)
+	for _, cfg := range cfgs {
+		vlan := vlans[cfg]
+		key := vlan.Device + " " + vlan.vid()
+		if other, ok := numbers[key]; ok && other != vlan.VLAN {
+			return fmt.Errorf("%w: vid %s is already used on switch %s by vlans %s and %s", ErrConflict, vlan.vid(), vlan.Device, other, vlan.VLAN)
+		}
+		numbers[key] = vlan.VLAN
+	}
+
+	return nil
+}
+
+func findSwitchVLAN(vlans map[string]SwitchVLAN, vlan SwitchVLAN) (string, bool) {
+	for cfg, current := range vlans {
+		if current.Device == vlan.Device && current.VLAN == vlan.VLAN {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
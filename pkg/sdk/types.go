@@ -1,12 +1,418 @@
 package sdk
 
-// DNSRecord represents a DNS record in LuciRPC
+import "time"
+
+// DNSRecord represents a DNS record in LuciRPC.
+// SRV and MX records keep their owner name in Name and the host they point
+// at in Target, with the preference of MX records stored as Priority.
 type DNSRecord struct {
+	Type     string `json:".type" validate:"required"`
+	IP       string `json:"ip,omitempty"`
+	Name     string `json:"name,omitempty"`
+	CName    string `json:"cname,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+	Port     string `json:"port,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// DNSRecordChange represents the replacement of a single DNS record
+type DNSRecordChange struct {
+	Old DNSRecord
+	New DNSRecord
+}
+
+// DNSRecordQuery selects DNS records, empty fields match any record
+type DNSRecordQuery struct {
+	// Type is the record type, e.g. A or CNAME
+	Type string
+	// Name matches the record name exactly, ignoring case
+	Name string
+	// Suffix matches the record name and every name below it
+	Suffix string
+	// IP matches A and AAAA records pointing at the address
+	IP string
+	// CIDR matches A and AAAA records pointing inside the prefix
+	CIDR string
+	// Owner matches the records created by an owner
+	Owner string
+}
+
+// DNSRecordConflict represents DNS records that should not coexist
+type DNSRecordConflict struct {
+	Kind     string
+	Name     string
+	Sections []string
+}
+
+// DNSImportPlan represents the changes importing DNS records would make
+type DNSImportPlan struct {
+	// Create holds the records whose name is not on the device yet
+	Create []DNSRecord
+	// Update holds the records replacing every current member of their name
+	Update []DNSRecord
+	// Unchanged holds the records already on the device
+	Unchanged []DNSRecord
+}
+
+// DnsmasqConfig represents a dnsmasq instance in LuciRPC
+type DnsmasqConfig struct {
+	Type             string  `json:".type" validate:"required"`
+	Domain           string  `json:"domain,omitempty"`
+	Local            string  `json:"local,omitempty"`
+	RebindProtection string  `json:"rebind_protection,omitempty"`
+	RebindDomain     UciList `json:"rebind_domain,omitempty"`
+	CacheSize        string  `json:"cachesize,omitempty"`
+	LocalService     string  `json:"localservice,omitempty"`
+	LogQueries       string  `json:"logqueries,omitempty"`
+	NoResolv         string  `json:"noresolv,omitempty"`
+	StrictOrder      string  `json:"strictorder,omitempty"`
+}
+
+// StaticLease represents a static DHCP lease (host section) in LuciRPC
+type StaticLease struct {
+	Type      string  `json:".type" validate:"required"`
+	Name      string  `json:"name,omitempty"`
+	MAC       UciList `json:"mac,omitempty"`
+	IP        string  `json:"ip,omitempty"`
+	LeaseTime string  `json:"leasetime,omitempty"`
+	DNS       string  `json:"dns,omitempty"`
+	Tag       UciList `json:"tag,omitempty"`
+}
+
+// DHCPPool represents the DHCP server of an interface (dhcp section) in LuciRPC
+type DHCPPool struct {
+	Type       string  `json:".type" validate:"required"`
+	Interface  string  `json:"interface,omitempty"`
+	Start      string  `json:"start,omitempty"`
+	Limit      string  `json:"limit,omitempty"`
+	LeaseTime  string  `json:"leasetime,omitempty"`
+	Ignore     string  `json:"ignore,omitempty"`
+	DHCPv4     string  `json:"dhcpv4,omitempty"`
+	DHCPv6     string  `json:"dhcpv6,omitempty"`
+	RA         string  `json:"ra,omitempty"`
+	DHCPOption UciList `json:"dhcp_option,omitempty"`
+}
+
+// DHCPTag represents a set of DHCP options sent to the clients carrying the
+// tag (tag section) in LuciRPC. Name is the tag, used as section name.
+type DHCPTag struct {
+	Type       string  `json:".type" validate:"required"`
+	Name       string  `json:".name"`
+	DHCPOption UciList `json:"dhcp_option,omitempty"`
+	Force      string  `json:"force,omitempty"`
+}
+
+// DHCPMatch represents a section setting a tag on the DHCP clients with a
+// MAC address (mac), vendor class (vendorclass) or user class (userclass)
+// in LuciRPC. Type selects which of MAC, VendorClass or UserClass is matched.
+type DHCPMatch struct {
+	Type        string  `json:".type" validate:"required"`
+	MAC         string  `json:"mac,omitempty"`
+	VendorClass string  `json:"vendorclass,omitempty"`
+	UserClass   string  `json:"userclass,omitempty"`
+	NetworkID   string  `json:"networkid,omitempty"`
+	DHCPOption  UciList `json:"dhcp_option,omitempty"`
+	Force       string  `json:"force,omitempty"`
+}
+
+// DnsmasqSet represents firewall sets filled by dnsmasq with the addresses
+// its domains resolve to (ipset section) in LuciRPC. Sets with a table are
+// nftables sets, the others are legacy ipsets.
+type DnsmasqSet struct {
+	Type        string  `json:".type" validate:"required"`
+	Name        UciList `json:"name,omitempty"`
+	Domain      UciList `json:"domain,omitempty"`
+	Table       string  `json:"table,omitempty"`
+	TableFamily string  `json:"table_family,omitempty"`
+}
+
+// Interface represents a logical network interface (interface section) in
+// LuciRPC. Name is the interface, used as section name.
+type Interface struct {
+	Type      string  `json:".type" validate:"required"`
+	Name      string  `json:".name"`
+	Proto     string  `json:"proto,omitempty"`
+	Device    string  `json:"device,omitempty"`
+	IPAddr    UciList `json:"ipaddr,omitempty"`
+	Netmask   string  `json:"netmask,omitempty"`
+	IP6Addr   UciList `json:"ip6addr,omitempty"`
+	Gateway   string  `json:"gateway,omitempty"`
+	DNS       UciList `json:"dns,omitempty"`
+	Metric    string  `json:"metric,omitempty"`
+	IP6Assign string  `json:"ip6assign,omitempty"`
+	IP6Hint   string  `json:"ip6hint,omitempty"`
+	Username  string  `json:"username,omitempty"`
+	Password  string  `json:"password,omitempty"`
+}
+
+// Route represents a static IPv4 (route section) or IPv6 (route6 section)
+// route in LuciRPC
+type Route struct {
+	Type      string `json:".type" validate:"required"`
+	Interface string `json:"interface,omitempty"`
+	// Target is a subnet in CIDR notation, or an IPv4 address with Netmask
+	Target  string `json:"target,omitempty"`
+	Netmask string `json:"netmask,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+	Metric  string `json:"metric,omitempty"`
+	// Table defaults to the main table
+	Table     string `json:"table,omitempty"`
+	RouteType string `json:"type,omitempty"`
+}
+
+// RouteOverlap represents routes of the same table whose targets overlap
+type RouteOverlap struct {
+	Table    string
+	Sections []string
+	Targets  []string
+}
+
+// Rule represents an IPv4 (rule section) or IPv6 (rule6 section) routing
+// policy rule in LuciRPC
+type Rule struct {
+	Type string `json:".type" validate:"required"`
+	// Section is the section name, which addresses rules without a priority
+	Section  string `json:".name,omitempty"`
+	In       string `json:"in,omitempty"`
+	Out      string `json:"out,omitempty"`
+	Src      string `json:"src,omitempty"`
+	Dest     string `json:"dest,omitempty"`
+	Lookup   string `json:"lookup,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	Mark     string `json:"mark,omitempty"`
+	Invert   string `json:"invert,omitempty"`
+}
+
+// Device represents a network device section in LuciRPC: a bridge, a VLAN
+// device (8021q or 8021ad), a macvlan or, without DeviceType, the settings
+// of an existing port
+type Device struct {
+	Type       string `json:".type" validate:"required"`
+	Name       string `json:"name,omitempty"`
+	DeviceType string `json:"type,omitempty"`
+	// Ports are the members of a bridge
+	Ports UciList `json:"ports,omitempty"`
+	// Ifname is the parent device of a VLAN device or macvlan
+	Ifname string `json:"ifname,omitempty"`
+	VID    string `json:"vid,omitempty"`
+	// Mode is the macvlan mode: private, vepa, bridge or passthru
+	Mode          string `json:"mode,omitempty"`
+	MACAddr       string `json:"macaddr,omitempty"`
+	MTU           string `json:"mtu,omitempty"`
+	STP           string `json:"stp,omitempty"`
+	VLANFiltering string `json:"vlan_filtering,omitempty"`
+}
+
+// BridgeVLAN represents the VLAN membership of the ports of a DSA bridge
+// (bridge-vlan section) in LuciRPC
+type BridgeVLAN struct {
+	Type   string `json:".type" validate:"required"`
+	Device string `json:"device,omitempty"`
+	VLAN   string `json:"vlan,omitempty"`
+	// Ports are written port[:t|:u][*], see VLANPort
+	Ports UciList `json:"ports,omitempty"`
+}
+
+// VLANPort represents the membership of a port in a bridge VLAN
+type VLANPort struct {
+	Name   string
+	Tagged bool
+	// PVID marks the VLAN untagged traffic entering the port belongs to
+	PVID bool
+}
+
+// SwitchVLAN represents a VLAN of a legacy swconfig switch (switch_vlan
+// section) in LuciRPC
+type SwitchVLAN struct {
 	Type   string `json:".type" validate:"required"`
-	IP     string `json:"ip,omitempty"`
-	Name   string `json:"name,omitempty"`
-	CName  string `json:"cname,omitempty"`
-	Target string `json:"target,omitempty"`
+	Device string `json:"device,omitempty"`
+	VLAN   string `json:"vlan,omitempty"`
+	// VID defaults to VLAN
+	VID string `json:"vid,omitempty"`
+	// Ports holds the switch port numbers separated by spaces, with a t
+	// suffix for tagged ports, e.g. "0t 2 3"
+	Ports       string `json:"ports,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// VLANModel is the way VLANs are configured on a router
+type VLANModel string
+
+const (
+	// VLANModelDSA configures VLANs with device and bridge-vlan sections
+	VLANModelDSA VLANModel = "dsa"
+	// VLANModelSwconfig configures VLANs with switch_vlan sections
+	VLANModelSwconfig VLANModel = "swconfig"
+)
+
+// WireGuardInterface represents a network interface with the wireguard
+// proto in LuciRPC
+type WireGuardInterface struct {
+	Type       string  `json:".type" validate:"required"`
+	Name       string  `json:".name"`
+	Proto      string  `json:"proto,omitempty"`
+	PrivateKey string  `json:"private_key,omitempty"`
+	ListenPort string  `json:"listen_port,omitempty"`
+	Addresses  UciList `json:"addresses,omitempty"`
+	MTU        string  `json:"mtu,omitempty"`
+	DNS        UciList `json:"dns,omitempty"`
+}
+
+// WireGuardPeer represents a peer of a WireGuard interface (wireguard_<iface>
+// section) in LuciRPC
+type WireGuardPeer struct {
+	Type                string  `json:".type" validate:"required"`
+	Description         string  `json:"description,omitempty"`
+	PublicKey           string  `json:"public_key,omitempty"`
+	PresharedKey        string  `json:"preshared_key,omitempty"`
+	AllowedIPs          UciList `json:"allowed_ips,omitempty"`
+	EndpointHost        string  `json:"endpoint_host,omitempty"`
+	EndpointPort        string  `json:"endpoint_port,omitempty"`
+	PersistentKeepalive string  `json:"persistent_keepalive,omitempty"`
+	RouteAllowedIPs     string  `json:"route_allowed_ips,omitempty"`
+}
+
+// WireGuardClient describes a new peer whose keys are generated by the SDK
+// and whose configuration is rendered for the client
+type WireGuardClient struct {
+	Description string
+	// Addresses are the tunnel addresses of the client, e.g. 10.0.0.2/32
+	Addresses []string
+	DNS       []string
+	// AllowedIPs are routed through the tunnel by the client, everything
+	// when empty
+	AllowedIPs []string
+	// Endpoint is the host or host:port of the router, the port defaulting
+	// to the listen port of the interface
+	Endpoint            string
+	PersistentKeepalive string
+	PresharedKey        bool
+}
+
+// WireGuardConfig represents a wg-quick configuration file
+type WireGuardConfig struct {
+	PrivateKey string
+	Addresses  []string
+	DNS        []string
+	ListenPort string
+	MTU        string
+	Peers      []WireGuardConfigPeer
+}
+
+// WireGuardImport describes a wg-quick configuration to create as a
+// WireGuard interface with its peers
+type WireGuardImport struct {
+	// Interface is the name of the new network interface
+	Interface string
+	Config    WireGuardConfig
+	// RouteAllowedIPs routes the allowed IPs of the peers through the tunnel,
+	// usually left off when Policy selects the traffic instead
+	RouteAllowedIPs bool
+	// Policy is created as a PBR policy of the new interface when it has a name
+	Policy PBR
+}
+
+// WireGuardConfigPeer represents a peer of a wg-quick configuration file
+type WireGuardConfigPeer struct {
+	PublicKey    string
+	PresharedKey string
+	AllowedIPs   []string
+	// Endpoint is written host:port
+	Endpoint            string
+	PersistentKeepalive string
+}
+
+// WireGuardStatus represents the runtime state of a WireGuard interface
+type WireGuardStatus struct {
+	Interface  string
+	PublicKey  string
+	ListenPort int
+	Peers      []WireGuardPeerStatus
+}
+
+// WireGuardPeerStatus represents the runtime state of a WireGuard peer
+type WireGuardPeerStatus struct {
+	PublicKey string
+	// Description comes from the peer section in UCI
+	Description string
+	// Endpoint is the host:port the peer was last seen at, empty until it
+	// connects
+	Endpoint   string
+	AllowedIPs []string
+	// LatestHandshake is zero when no handshake ever completed
+	LatestHandshake     time.Time
+	ReceivedBytes       uint64
+	SentBytes           uint64
+	PersistentKeepalive time.Duration
+}
+
+// InterfaceStatus represents the runtime state of a logical network
+// interface reported by netifd
+type InterfaceStatus struct {
+	Name      string
+	Up        bool
+	Pending   bool
+	Available bool
+	Uptime    time.Duration
+	Proto     string
+	Device    string
+	// L3Device is the device carrying the addresses, e.g. pppoe-wan
+	L3Device string
+	// IPv4 and IPv6 hold addresses in CIDR notation
+	IPv4   []string
+	IPv6   []string
+	Routes []InterfaceRoute
+	DNS    []string
+	// DelegatedPrefixes are the IPv6 prefixes received from upstream
+	DelegatedPrefixes []string
+}
+
+// InterfaceRoute represents a route installed by netifd for an interface
+type InterfaceRoute struct {
+	// Target is a subnet in CIDR notation
+	Target  string
+	Nexthop string
+	Metric  int
+}
+
+// Host represents a server made of a static lease, the A and AAAA records
+// of its name and the CNAME records pointing at it
+type Host struct {
+	Name    string
+	MAC     []string
+	IPv4    string
+	IPv6    []string
+	Aliases []string
+}
+
+// IPAllocation describes where to look for a free IPv4 address
+type IPAllocation struct {
+	// Interface selects the subnet of a static network interface
+	Interface string
+	// CIDR selects an explicit subnet when Interface is empty
+	CIDR string
+	// Exclude holds addresses, subnets or ranges (first-last) never allocated
+	Exclude []string
+}
+
+// DHCPLease represents an active DHCP or DHCPv6 lease
+type DHCPLease struct {
+	MAC      string
+	IP       string
+	Hostname string
+	// ClientID is the DHCP client identifier or the DHCPv6 DUID
+	ClientID string
+	// Expiry is zero for leases that never expire
+	Expiry time.Time
+}
+
+// DHCPLeases represents the active leases of the DHCP servers
+type DHCPLeases struct {
+	IPv4 []DHCPLease
+	IPv6 []DHCPLease
 }
 
 // PBR represents a Policy Based Routering in LuciRPC
@@ -14,7 +420,86 @@ type PBR struct {
 	Type      string `json:".type" validate:"required"`
 	Name      string `json:"name,omitempty"`
 	SrcAddr   string `json:"src_addr,omitempty"`
+	SrcPort   string `json:"src_port,omitempty"`
 	Enabled   string `json:"enabled,omitempty"`
 	DsrAddr   string `json:"dest_addr,omitempty"`
+	DestPort  string `json:"dest_port,omitempty"`
+	Proto     string `json:"proto,omitempty"`
+	Chain     string `json:"chain,omitempty"`
 	Interface string `json:"interface,omitempty"`
 }
+
+// OrderedPBR represents a PBR policy with its section name and its position
+// among the policies, which PBR evaluates in order
+type OrderedPBR struct {
+	Section string
+	Index   int
+	PBR
+}
+
+// PBRSchedule describes when a PBR policy is enabled: inside any of its
+// windows, and disabled outside of them
+type PBRSchedule struct {
+	// Policy is the name or section ID of the policy
+	Policy  string
+	Windows []ScheduleWindow
+}
+
+// ScheduleWindow is a period repeating every week between Start and End on
+// Days, or starting at every match of the Cron expression and lasting
+// Duration when Cron is set
+type ScheduleWindow struct {
+	// Days defaults to every day
+	Days []time.Weekday
+	// Start and End are times of day as HH:MM, End before Start making the
+	// window end on the next day
+	Start string
+	End   string
+	// Cron is a five field expression: minute, hour, day of month, month
+	// and day of week
+	Cron     string
+	Duration time.Duration
+}
+
+// PBRDNSPolicy represents a PBR policy sending the DNS queries of clients
+// to another resolver (dns_policy section) in LuciRPC
+type PBRDNSPolicy struct {
+	Type    string `json:".type" validate:"required"`
+	Name    string `json:"name,omitempty"`
+	SrcAddr string `json:"src_addr,omitempty"`
+	DestDNS string `json:"dest_dns,omitempty"`
+	Enabled string `json:"enabled,omitempty"`
+}
+
+// PBRInclude represents a user script run by PBR (include section) in LuciRPC
+type PBRInclude struct {
+	Type    string `json:".type" validate:"required"`
+	Path    string `json:"path,omitempty"`
+	Enabled string `json:"enabled,omitempty"`
+}
+
+// PBRConfig represents the global settings of PBR (config section) in LuciRPC
+type PBRConfig struct {
+	Type                   string  `json:".type" validate:"required"`
+	Enabled                string  `json:"enabled,omitempty"`
+	StrictEnforcement      string  `json:"strict_enforcement,omitempty"`
+	ResolverSet            string  `json:"resolver_set,omitempty"`
+	IPv6Enabled            string  `json:"ipv6_enabled,omitempty"`
+	SupportedInterface     UciList `json:"supported_interface,omitempty"`
+	IgnoredInterface       UciList `json:"ignored_interface,omitempty"`
+	BootTimeout            string  `json:"boot_timeout,omitempty"`
+	Verbosity              string  `json:"verbosity,omitempty"`
+	WebUIShowIgnoreTarget  string  `json:"webui_show_ignore_target,omitempty"`
+	WebUISupportedProtocol UciList `json:"webui_supported_protocol,omitempty"`
+}
+
+// PBRStatus represents the state of the PBR service
+type PBRStatus struct {
+	// Enabled reports whether the service starts at boot
+	Enabled bool
+	Running bool
+	// Version is empty when the LuCI PBR application is not installed
+	Version  string
+	Errors   []string
+	Warnings []string
+}
@@ -0,0 +1,262 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrSectionNotFound is returned when a UCI section does not exist
+	ErrSectionNotFound = errors.New("section not found")
+	// ErrConflict is returned when a change clashes with the current config
+	ErrConflict = errors.New("conflict")
+	// ErrAmbiguous is returned when a name matches several UCI sections
+	ErrAmbiguous = errors.New("ambiguous name")
+)
+
+// UciList represents a UCI list option. LuciRPC returns a plain string when
+// the option was written as a single value, which is decoded as one item.
+type UciList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *UciList) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*l = UciList{value}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*l = values
+	return nil
+}
+
+// getSections retrieves the sections of the kinds from config, keyed by section name.
+func getSections[T any](ctx context.Context, o *OpenWRT, config string, kinds ...string) (map[string]T, error) {
+	result, err := o.lucirpc.Uci(ctx, "get_all", []string{config})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	err = json.Unmarshal([]byte(result), &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string]T)
+	for name, data := range raw {
+		var meta struct {
+			Type string `json:".type"`
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(kinds, meta.Type) {
+			continue
+		}
+
+		var section T
+		if err := json.Unmarshal(data, &section); err != nil {
+			return nil, err
+		}
+		sections[name] = section
+	}
+
+	return sections, nil
+}
+
+// getSection retrieves a single section of a config into section.
+func (o *OpenWRT) getSection(ctx context.Context, config, name string, section any) error {
+	result, err := o.lucirpc.Uci(ctx, "get_all", []string{config, name})
+	if err != nil {
+		return err
+	}
+
+	if result == "" || result == "null" || result == "false" {
+		return fmt.Errorf("%w: %s.%s", ErrSectionNotFound, config, name)
+	}
+
+	return json.Unmarshal([]byte(result), section)
+}
+
+// addSection adds an anonymous section of kind to config and sets the
+// non-empty options of section, returning the name of the new section.
+func (o *OpenWRT) addSection(ctx context.Context, config, kind string, section any) (string, error) {
+	cfg, err := o.lucirpc.Uci(ctx, "add", []string{config, kind})
+	if err != nil {
+		return "", err
+	}
+
+	return cfg, o.setOptions(ctx, config, cfg, section, nil)
+}
+
+// revertConfigs drops the uncommitted changes of configs after err, which is
+// returned along with the first error reverting them.
+func (o *OpenWRT) revertConfigs(ctx context.Context, configs []string, err error) error {
+	for _, config := range configs {
+		if _, revertErr := o.lucirpc.Uci(ctx, "revert", []string{config}); revertErr != nil {
+			return fmt.Errorf("%w, reverting %s: %v", err, config, revertErr)
+		}
+	}
+
+	return err
+}
+
+// addNamedSection adds a section of kind called name to config and sets the
+// non-empty options of section. As uci set changes the type of an existing
+// section, a name already used by a section of any type is a conflict.
+func (o *OpenWRT) addNamedSection(ctx context.Context, config, kind, name string, section any) error {
+	kinds, err := o.sectionKinds(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if err := checkSectionName(kinds, config, name); err != nil {
+		return err
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "set", []string{config, name, kind}); err != nil {
+		return err
+	}
+
+	return o.setOptions(ctx, config, name, section, nil)
+}
+
+// sectionKinds returns the type of every section of config, keyed by section name.
+func (o *OpenWRT) sectionKinds(ctx context.Context, config string) (map[string]string, error) {
+	result, err := o.lucirpc.Uci(ctx, "get_all", []string{config})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]struct {
+		Type string `json:".type"`
+	}
+	if err := json.Unmarshal([]byte(result), &raw); err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[string]string, len(raw))
+	for name, meta := range raw {
+		kinds[name] = meta.Type
+	}
+
+	return kinds, nil
+}
+
+// checkSectionName returns ErrConflict when a section of config, whatever
+// its type, is already called name.
+func checkSectionName(kinds map[string]string, config, name string) error {
+	if kind, ok := kinds[name]; ok {
+		return fmt.Errorf("%w: %s.%s already exists as a %s section", ErrConflict, config, name, kind)
+	}
+
+	return nil
+}
+
+// updateSection writes the options of section over the existing section
+// name of config, deleting the options left empty.
+func (o *OpenWRT) updateSection(ctx context.Context, config, name string, section any) error {
+	var current map[string]json.RawMessage
+	if err := o.getSection(ctx, config, name, &current); err != nil {
+		return err
+	}
+
+	return o.setOptions(ctx, config, name, section, current)
+}
+
+// setOptions sets the options of section that differ from current and
+// deletes the empty ones still present in current.
+func (o *OpenWRT) setOptions(ctx context.Context, config, name string, section any, current map[string]json.RawMessage) error {
+	for _, option := range sectionOptions(section) {
+		currentValue, exists := current[option.name]
+
+		switch value := option.value.(type) {
+		case string:
+			if value == "" {
+				if exists {
+					if _, err := o.lucirpc.Uci(ctx, "delete", []string{config, name, option.name}); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			var currentString string
+			if exists && json.Unmarshal(currentValue, &currentString) == nil && currentString == value {
+				continue
+			}
+
+			if _, err := o.lucirpc.Uci(ctx, "set", []string{config, name, option.name, value}); err != nil {
+				return err
+			}
+		case []string:
+			if len(value) == 0 {
+				if exists {
+					if _, err := o.lucirpc.Uci(ctx, "delete", []string{config, name, option.name}); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			var currentList UciList
+			if exists && json.Unmarshal(currentValue, &currentList) == nil && reflect.DeepEqual([]string(currentList), value) {
+				continue
+			}
+
+			if _, err := o.lucirpc.UciSetList(ctx, config, name, option.name, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type sectionOption struct {
+	name  string
+	value any
+}
+
+// sectionOptions returns the string and list fields of a section struct
+// named by their json tag, skipping the metadata fields starting with a dot.
+func sectionOptions(section any) []sectionOption {
+	value := reflect.Indirect(reflect.ValueOf(section))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var options []sectionOption
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Type().Field(index)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		switch fieldValue := value.Field(index); {
+		case fieldValue.Kind() == reflect.String:
+			options = append(options, sectionOption{name: name, value: fieldValue.String()})
+		case fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.String:
+			list := make([]string, fieldValue.Len())
+			for item := range list {
+				list[item] = fieldValue.Index(item).String()
+			}
+			options = append(options, sectionOption{name: name, value: list})
+		}
+	}
+
+	return options
+}
@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// validateDNSName checks the syntax of a domain name, a trailing dot is allowed.
+func validateDNSName(field, name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("invalid %s: %s", field, name)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("invalid %s: %s", field, name)
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("invalid %s: %s", field, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBool checks a UCI boolean option, empty meaning the default.
+func validateBool(field, value string) error {
+	switch value {
+	case "", "0", "1":
+		return nil
+	default:
+		return fmt.Errorf("invalid %s: %s, expected 0 or 1", field, value)
+	}
+}
+
+// validateUint checks an unsigned integer option in [min, max], empty
+// meaning the default.
+func validateUint(field, value string, min, max uint64) error {
+	if value == "" {
+		return nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || number < min || number > max {
+		return fmt.Errorf("invalid %s: %s, expected a number between %d and %d", field, value, min, max)
+	}
+
+	return nil
+}
+
+// validateMAC checks a MAC address, dnsmasq allows * as a wildcard byte.
+func validateMAC(field, mac string) error {
+	if _, err := net.ParseMAC(strings.ReplaceAll(mac, "*", "00")); err != nil {
+		return fmt.Errorf("invalid %s: %s", field, mac)
+	}
+
+	return nil
+}
+
+// validateIPv4 checks an IPv4 address.
+func validateIPv4(field, ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("invalid %s: %s, expected an IPv4 address", field, ip)
+	}
+
+	return nil
+}
+
+// validateLeaseTime checks a dnsmasq lease time such as 12h, 3600 or infinite,
+// empty meaning the default.
+func validateLeaseTime(field, value string) error {
+	if value == "" || value == "infinite" {
+		return nil
+	}
+
+	number := strings.TrimRight(value, "smhdw")
+	if len(value)-len(number) > 1 {
+		return fmt.Errorf("invalid %s: %s", field, value)
+	}
+
+	if _, err := strconv.ParseUint(number, 10, 32); err != nil {
+		return fmt.Errorf("invalid %s: %s", field, value)
+	}
+
+	return nil
+}
+
+// validateUciName checks a UCI section name, made of letters, digits and underscores.
+func validateUciName(field, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return fmt.Errorf("invalid %s: %s, expected letters, digits or underscores", field, name)
+		}
+	}
+
+	return nil
+}
+
+// validateDHCPOption checks a DHCP option written as code,value.
+func validateDHCPOption(option string) error {
+	if code, _, ok := strings.Cut(option, ","); !ok || code == "" {
+		return fmt.Errorf("invalid dhcp option: %s, expected code,value", option)
+	}
+
+	return nil
+}
+
+// validateDeviceName checks a Linux network device name, at most 15
+// characters without spaces, slashes or colons.
+func validateDeviceName(field, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+
+	if len(name) > 15 || strings.ContainsAny(name, " \t\n/:") {
+		return fmt.Errorf("invalid %s: %s", field, name)
+	}
+
+	return nil
+}
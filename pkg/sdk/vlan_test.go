@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("VLANs", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+	})
+
+	Context("on a DSA router", func() {
+		BeforeEach(func() {
+			router.AddSection("network", "device", "", map[string]any{
+				"name":  "br-lan",
+				"type":  "bridge",
+				"ports": []string{"lan1", "lan2", "lan3"},
+			})
+			router.AddSection("network", "bridge-vlan", "", map[string]any{
+				"device": "br-lan",
+				"vlan":   "1",
+				"ports":  []string{"lan1:u*", "lan2:u*", "lan3:t"},
+			})
+		})
+
+		It("detect DSA", func() {
+			o := newFakeOpenWRT(router)
+			Expect(o.GetVLANModel(ctx)).To(Equal(VLANModelDSA))
+		})
+
+		It("create, update and delete a bridge VLAN", func() {
+			o := newFakeOpenWRT(router)
+			vlan := BridgeVLAN{Device: "br-lan", VLAN: "10", Ports: UciList{
+				VLANPort{Name: "lan3", PVID: true}.String(),
+				VLANPort{Name: "lan1", Tagged: true}.String(),
+			}}
+			Expect(o.SetBridgeVLANs(ctx, []BridgeVLAN{vlan})).To(Succeed())
+			Expect(o.GetBridgeVLANs(ctx)).To(HaveKeyWithValue("cfg3", BridgeVLAN{
+				Type:   "bridge-vlan",
+				Device: "br-lan",
+				VLAN:   "10",
+				Ports:  UciList{"lan3:u*", "lan1:t"},
+			}))
+
+			err := o.SetBridgeVLANs(ctx, []BridgeVLAN{vlan})
+			Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+			err = o.SetBridgeVLANs(ctx, []BridgeVLAN{{Device: "lan1", VLAN: "20"}})
+			Expect(err).To(MatchError("invalid device: lan1, not a bridge"))
+
+			vlan.Ports = UciList{"lan1:t*", "lan3:t"}
+			err = o.UpdateBridgeVLANs(ctx, []BridgeVLAN{vlan})
+			Expect(err).To(MatchError(ContainSubstring("port lan1 of bridge br-lan is the pvid of vlans 1 and 10")))
+			Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+			vlan.Ports = UciList{"lan3:t"}
+			Expect(o.UpdateBridgeVLANs(ctx, []BridgeVLAN{vlan})).To(Succeed())
+			Expect(o.DeleteBridgeVLANs(ctx, []BridgeVLAN{vlan})).To(Succeed())
+			Expect(router.Sections("network", "bridge-vlan")).To(HaveLen(1))
+			Expect(o.DeleteBridgeVLANs(ctx, []BridgeVLAN{vlan})).To(MatchError("bridge vlans not found: [br-lan.10]"))
+			Expect(router.Commits("network")).To(Equal(3))
+		})
+
+		It("refuse switch VLANs", func() {
+			o := newFakeOpenWRT(router)
+			err := o.SetSwitchVLANs(ctx, []SwitchVLAN{{Device: "switch0", VLAN: "1", Ports: "0t 1"}})
+			Expect(err).To(MatchError("switch vlans are not supported on dsa routers"))
+		})
+	})
+
+	Context("on a swconfig router", func() {
+		BeforeEach(func() {
+			router.AddSection("network", "switch", "", map[string]any{"name": "switch0", "reset": "1", "enable_vlan": "1"})
+			router.AddSection("network", "switch_vlan", "", map[string]any{"device": "switch0", "vlan": "1", "ports": "0t 1 2 3"})
+			router.AddSection("network", "switch_vlan", "", map[string]any{"device": "switch0", "vlan": "2", "ports": "0t 4"})
+		})
+
+		It("detect swconfig", func() {
+			o := newFakeOpenWRT(router)
+			Expect(o.GetVLANModel(ctx)).To(Equal(VLANModelSwconfig))
+		})
+
+		It("create, update and delete a switch VLAN", func() {
+			o := newFakeOpenWRT(router)
+			vlan := SwitchVLAN{Device: "switch0", VLAN: "3", VID: "10", Ports: "0t 3t", Description: "iot"}
+			Expect(o.SetSwitchVLANs(ctx, []SwitchVLAN{vlan})).To(Succeed())
+			Expect(o.GetSwitchVLANs(ctx)).To(HaveKeyWithValue("cfg4", SwitchVLAN{
+				Type:        "switch_vlan",
+				Device:      "switch0",
+				VLAN:        "3",
+				VID:         "10",
+				Ports:       "0t 3t",
+				Description: "iot",
+			}))
+
+			err := o.SetSwitchVLANs(ctx, []SwitchVLAN{{Device: "switch0", VLAN: "4", VID: "2", Ports: "0t"}})
+			Expect(err).To(MatchError(ContainSubstring("vid 2 is already used on switch switch0")))
+			err = o.SetSwitchVLANs(ctx, []SwitchVLAN{{Device: "switch1", VLAN: "4", Ports: "0t"}})
+			Expect(err).To(MatchError("invalid device: switch1, not a switch"))
+
+			err = o.UpdateSwitchVLANs(ctx, []SwitchVLAN{{Device: "switch0", VLAN: "3", VID: "1", Ports: "0t 3t"}})
+			Expect(err).To(MatchError(ContainSubstring("vid 1 is already used on switch switch0 by vlans 1 and 3")))
+			Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+			err = o.UpdateSwitchVLANs(ctx, []SwitchVLAN{
+				{Device: "switch0", VLAN: "1", VID: "20", Ports: "0t 1 2 3"},
+				{Device: "switch0", VLAN: "2", VID: "20", Ports: "0t 4"},
+			})
+			Expect(err).To(MatchError(ContainSubstring("vid 20 is already used on switch switch0 by vlans 1 and 2")))
+			Expect(router.Commits("network")).To(Equal(1))
+
+			vlan.Description = ""
+			Expect(o.UpdateSwitchVLANs(ctx, []SwitchVLAN{vlan})).To(Succeed())
+			Expect(router.Sections("network", "switch_vlan")).To(ContainElement(And(
+				HaveKeyWithValue("vlan", "3"),
+				Not(HaveKey("description")),
+			)))
+
+			Expect(o.DeleteSwitchVLANs(ctx, []SwitchVLAN{vlan})).To(Succeed())
+			Expect(router.Sections("network", "switch_vlan")).To(HaveLen(2))
+			Expect(router.Commits("network")).To(Equal(3))
+		})
+
+		It("refuse bridge VLANs", func() {
+			o := newFakeOpenWRT(router)
+			err := o.SetBridgeVLANs(ctx, []BridgeVLAN{{Device: "br-lan", VLAN: "10"}})
+			Expect(err).To(MatchError("bridge vlans are not supported on swconfig routers"))
+		})
+	})
+
+	It("parse VLAN ports", func() {
+		Expect(ParseVLANPort("lan1")).To(Equal(VLANPort{Name: "lan1"}))
+		Expect(ParseVLANPort("lan1:t")).To(Equal(VLANPort{Name: "lan1", Tagged: true}))
+		Expect(ParseVLANPort("lan1:u*")).To(Equal(VLANPort{Name: "lan1", PVID: true}))
+		Expect(ParseVLANPort("lan1*")).To(Equal(VLANPort{Name: "lan1", PVID: true}))
+		Expect(ParseVLANPort("lan1:t*")).To(Equal(VLANPort{Name: "lan1", Tagged: true, PVID: true}))
+		_, err := ParseVLANPort("lan1:x")
+		Expect(err).To(MatchError("invalid port: lan1:x, expected port[:t|:u][*]"))
+		_, err = ParseVLANPort(":t")
+		Expect(err).ToNot(BeNil())
+		Expect(VLANPort{Name: "lan2", Tagged: true, PVID: true}.String()).To(Equal("lan2:t*"))
+	})
+
+	It("validate", func() {
+		invalidBridgeVLANs := []BridgeVLAN{
+			{VLAN: "10"},
+			{Device: "br-lan"},
+			{Device: "br-lan", VLAN: "0"},
+			{Device: "br-lan", VLAN: "10", Ports: UciList{"lan1:t", "lan1:u"}},
+		}
+		for _, vlan := range invalidBridgeVLANs {
+			Expect(vlan.Validate()).ToNot(Succeed(), "%+v", vlan)
+		}
+
+		invalidSwitchVLANs := []SwitchVLAN{
+			{VLAN: "1", Ports: "0t"},
+			{Device: "switch0", Ports: "0t"},
+			{Device: "switch0", VLAN: "1"},
+			{Device: "switch0", VLAN: "1", VID: "4095", Ports: "0t"},
+			{Device: "switch0", VLAN: "1", Ports: "0x"},
+			{Device: "switch0", VLAN: "1", Ports: "0t 0"},
+		}
+		for _, vlan := range invalidSwitchVLANs {
+			Expect(vlan.Validate()).ToNot(Succeed(), "%+v", vlan)
+		}
+	})
+})
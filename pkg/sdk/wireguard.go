@@ -0,0 +1,503 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// GetWireGuardInterfaces retrieves the WireGuard interfaces from the OpenWRT
+// device, keyed by name.
+func (o *OpenWRT) GetWireGuardInterfaces(ctx context.Context) (map[string]WireGuardInterface, error) {
+	ifaces, err := getSections[WireGuardInterface](ctx, o, "network", "interface")
+	if err != nil {
+		return nil, err
+	}
+
+	for name, iface := range ifaces {
+		if iface.Proto != "wireguard" {
+			delete(ifaces, name)
+		}
+	}
+
+	return ifaces, nil
+}
+
+// SetWireGuardInterfaces adds WireGuard interfaces to the OpenWRT device,
+// then commits and reloads the network. A private key is generated for the
+// interfaces without one. Nothing is written when an interface is invalid
+// or its name is already used by a section of the network config.
+func (o *OpenWRT) SetWireGuardInterfaces(ctx context.Context, ifaces []WireGuardInterface) error {
+	kinds, err := o.sectionKinds(ctx, "network")
+	if err != nil {
+		return err
+	}
+
+	ifaces = slices.Clone(ifaces)
+	for index := range ifaces {
+		iface := &ifaces[index]
+		iface.Proto = "wireguard"
+		if iface.PrivateKey == "" {
+			if iface.PrivateKey, _, err = GenerateWireGuardKeyPair(); err != nil {
+				return err
+			}
+		}
+
+		if err := iface.Validate(); err != nil {
+			return err
+		}
+
+		if err := checkSectionName(kinds, "network", iface.Name); err != nil {
+			return err
+		}
+		kinds[iface.Name] = "interface"
+	}
+
+	for _, iface := range ifaces {
+		if err := o.addNamedSection(ctx, "network", "interface", iface.Name, iface); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateWireGuardInterfaces updates existing WireGuard interfaces, matched
+// by name, then commits and reloads the network. Empty fields are removed
+// from the interface.
+func (o *OpenWRT) UpdateWireGuardInterfaces(ctx context.Context, updateIfaces []WireGuardInterface) error {
+	currentIfaces, err := o.GetWireGuardInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	updateIfaces = slices.Clone(updateIfaces)
+	var notFound []string
+	for index := range updateIfaces {
+		iface := &updateIfaces[index]
+		iface.Proto = "wireguard"
+		if err := iface.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := currentIfaces[iface.Name]; !ok {
+			notFound = append(notFound, iface.Name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("wireguard interfaces not found: %v", notFound)
+	}
+
+	for _, iface := range updateIfaces {
+		if err := o.updateSection(ctx, "network", iface.Name, iface); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteWireGuardInterfaces deletes WireGuard interfaces and their peers from
+// the OpenWRT device, then commits and reloads the network.
+func (o *OpenWRT) DeleteWireGuardInterfaces(ctx context.Context, names []string) error {
+	currentIfaces, err := o.GetWireGuardInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notFound []string
+	for _, name := range names {
+		if _, ok := currentIfaces[name]; !ok {
+			notFound = append(notFound, name)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("wireguard interfaces not found: %v", notFound)
+	}
+
+	for _, name := range names {
+		peers, err := o.GetWireGuardPeers(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		for cfg := range peers {
+			if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", name}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// GetWireGuardPeers retrieves the peers of a WireGuard interface, keyed by
+// section name.
+func (o *OpenWRT) GetWireGuardPeers(ctx context.Context, iface string) (map[string]WireGuardPeer, error) {
+	return getSections[WireGuardPeer](ctx, o, "network", wireGuardPeerType(iface))
+}
+
+// SetWireGuardPeers adds peers to a WireGuard interface, then commits and
+// reloads the network. Nothing is written when a peer is invalid, its public
+// key is already a peer or one of its allowed IPs belongs to another peer.
+func (o *OpenWRT) SetWireGuardPeers(ctx context.Context, iface string, peers []WireGuardPeer) error {
+	currentPeers, err := o.wireGuardPeers(ctx, iface)
+	if err != nil {
+		return err
+	}
+
+	for index, peer := range peers {
+		peer.Type = wireGuardPeerType(iface)
+		if err := peer.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findWireGuardPeer(currentPeers, peer.PublicKey); ok {
+			return fmt.Errorf("%w: %s is already a peer of %s", ErrConflict, peer.PublicKey, iface)
+		}
+		currentPeers[fmt.Sprintf("new peer %d", index)] = peer
+	}
+
+	if err := checkWireGuardPeers(currentPeers); err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if _, err := o.addSection(ctx, "network", wireGuardPeerType(iface), peer); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// UpdateWireGuardPeers updates existing peers of a WireGuard interface,
+// matched by public key, then commits and reloads the network. Empty fields
+// are removed from the peer.
+func (o *OpenWRT) UpdateWireGuardPeers(ctx context.Context, iface string, updatePeers []WireGuardPeer) error {
+	currentPeers, err := o.wireGuardPeers(ctx, iface)
+	if err != nil {
+		return err
+	}
+
+	cfgs := make([]string, len(updatePeers))
+	var notFound []string
+	for index, peer := range updatePeers {
+		peer.Type = wireGuardPeerType(iface)
+		if err := peer.Validate(); err != nil {
+			return err
+		}
+
+		cfg, ok := findWireGuardPeer(currentPeers, peer.PublicKey)
+		if !ok {
+			notFound = append(notFound, peer.PublicKey)
+			continue
+		}
+		cfgs[index] = cfg
+		currentPeers[cfg] = peer
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("wireguard peers not found: %v", notFound)
+	}
+
+	if err := checkWireGuardPeers(currentPeers); err != nil {
+		return err
+	}
+
+	for index, peer := range updatePeers {
+		if err := o.updateSection(ctx, "network", cfgs[index], peer); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// DeleteWireGuardPeers deletes peers from a WireGuard interface by public
+// key, then commits and reloads the network.
+func (o *OpenWRT) DeleteWireGuardPeers(ctx context.Context, iface string, publicKeys []string) error {
+	currentPeers, err := o.GetWireGuardPeers(ctx, iface)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cfgs     []string
+		notFound []string
+	)
+	for _, publicKey := range publicKeys {
+		cfg, ok := findWireGuardPeer(currentPeers, publicKey)
+		if !ok {
+			notFound = append(notFound, publicKey)
+			continue
+		}
+
+		if !slices.Contains(cfgs, cfg) {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("wireguard peers not found: %v", notFound)
+	}
+
+	for _, cfg := range cfgs {
+		if _, err := o.lucirpc.Uci(ctx, "delete", []string{"network", cfg}); err != nil {
+			return err
+		}
+	}
+
+	return o.commitNetwork(ctx)
+}
+
+// AddWireGuardClient generates the keys of a new client, adds it as a peer
+// of a WireGuard interface and returns its wg-quick configuration file.
+func (o *OpenWRT) AddWireGuardClient(ctx context.Context, iface string, client WireGuardClient) (string, error) {
+	ifaces, err := o.GetWireGuardInterfaces(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	server, ok := ifaces[iface]
+	if !ok {
+		return "", fmt.Errorf("%w: wireguard interface %s", ErrSectionNotFound, iface)
+	}
+
+	serverKey, err := WireGuardPublicKey(server.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, err := wireGuardEndpoint(client.Endpoint, server.ListenPort)
+	if err != nil {
+		return "", err
+	}
+
+	if len(client.Addresses) == 0 {
+		return "", fmt.Errorf("addresses is required")
+	}
+
+	peer := WireGuardPeer{Description: client.Description, RouteAllowedIPs: "1"}
+	for _, address := range client.Addresses {
+		prefix, err := parseWireGuardPrefix("addresses", address)
+		if err != nil {
+			return "", err
+		}
+
+		// the router routes only the address of the client to it
+		host := netip.PrefixFrom(prefix.Addr(), prefix.Addr().BitLen())
+		peer.AllowedIPs = append(peer.AllowedIPs, host.String())
+	}
+
+	for _, dns := range client.DNS {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return "", fmt.Errorf("invalid dns: %s", dns)
+		}
+	}
+
+	privateKey, publicKey, err := GenerateWireGuardKeyPair()
+	if err != nil {
+		return "", err
+	}
+	peer.PublicKey = publicKey
+
+	if client.PresharedKey {
+		if peer.PresharedKey, err = GenerateWireGuardPresharedKey(); err != nil {
+			return "", err
+		}
+	}
+
+	allowedIPs := client.AllowedIPs
+	if len(allowedIPs) == 0 {
+		allowedIPs = []string{"0.0.0.0/0", "::/0"}
+	}
+
+	config := WireGuardConfig{
+		PrivateKey: privateKey,
+		Addresses:  client.Addresses,
+		DNS:        client.DNS,
+		Peers: []WireGuardConfigPeer{{
+			PublicKey:           serverKey,
+			PresharedKey:        peer.PresharedKey,
+			AllowedIPs:          allowedIPs,
+			Endpoint:            endpoint,
+			PersistentKeepalive: client.PersistentKeepalive,
+		}},
+	}
+	if err := config.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := o.SetWireGuardPeers(ctx, iface, []WireGuardPeer{peer}); err != nil {
+		return "", err
+	}
+
+	return config.String(), nil
+}
+
+// Validate checks the fields of a WireGuard interface.
+func (i WireGuardInterface) Validate() error {
+	if err := validateUciName("name", i.Name); err != nil {
+		return err
+	}
+
+	if err := validateWireGuardKey("private_key", i.PrivateKey); err != nil {
+		return err
+	}
+
+	if err := validateUint("listen_port", i.ListenPort, 1, 65535); err != nil {
+		return err
+	}
+
+	for _, address := range i.Addresses {
+		if _, err := parseWireGuardPrefix("addresses", address); err != nil {
+			return err
+		}
+	}
+
+	for _, dns := range i.DNS {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return fmt.Errorf("invalid dns: %s", dns)
+		}
+	}
+
+	return validateUint("mtu", i.MTU, 1280, 65535)
+}
+
+// Validate checks the fields of a WireGuard peer.
+func (p WireGuardPeer) Validate() error {
+	if err := validateWireGuardKey("public_key", p.PublicKey); err != nil {
+		return err
+	}
+
+	if p.PresharedKey != "" {
+		if err := validateWireGuardKey("preshared_key", p.PresharedKey); err != nil {
+			return err
+		}
+	}
+
+	for _, allowedIP := range p.AllowedIPs {
+		if _, err := parseWireGuardPrefix("allowed_ips", allowedIP); err != nil {
+			return err
+		}
+	}
+
+	if strings.ContainsFunc(p.EndpointHost, unicode.IsSpace) {
+		return fmt.Errorf("invalid endpoint_host: %s", p.EndpointHost)
+	}
+
+	if p.EndpointHost == "" && p.EndpointPort != "" {
+		return fmt.Errorf("endpoint_host is required with endpoint_port")
+	}
+
+	if err := validateUint("endpoint_port", p.EndpointPort, 1, 65535); err != nil {
+		return err
+	}
+
+	if err := validateUint("persistent_keepalive", p.PersistentKeepalive, 0, 65535); err != nil {
+		return err
+	}
+
+	return validateBool("route_allowed_ips", p.RouteAllowedIPs)
+}
+
+// wireGuardPeers retrieves the peers of iface, failing when iface is not a
+// WireGuard interface.
+func (o *OpenWRT) wireGuardPeers(ctx context.Context, iface string) (map[string]WireGuardPeer, error) {
+	ifaces, err := o.GetWireGuardInterfaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ifaces[iface]; !ok {
+		return nil, fmt.Errorf("%w: wireguard interface %s", ErrSectionNotFound, iface)
+	}
+
+	return o.GetWireGuardPeers(ctx, iface)
+}
+
+// checkWireGuardPeers checks that no allowed IP belongs to two peers, as
+// WireGuard routes each of them to a single peer.
+func checkWireGuardPeers(peers map[string]WireGuardPeer) error {
+	cfgs := make([]string, 0, len(peers))
+	for cfg := range peers {
+		cfgs = append(cfgs, cfg)
+	}
+	slices.Sort(cfgs)
+
+	owners := make(map[netip.Prefix]string)
+	for _, cfg := range cfgs {
+		peer := peers[cfg]
+		for _, allowedIP := range peer.AllowedIPs {
+			prefix, err := parseWireGuardPrefix("allowed_ips", allowedIP)
+			if err != nil {
+				continue
+			}
+			prefix = prefix.Masked()
+
+			if owner, ok := owners[prefix]; ok && owner != peer.PublicKey {
+				return fmt.Errorf("%w: allowed ip %s belongs to peers %s and %s", ErrConflict, prefix, owner, peer.PublicKey)
+			}
+			owners[prefix] = peer.PublicKey
+		}
+	}
+
+	return nil
+}
+
+// parseWireGuardPrefix parses a subnet, or an address taken as a single host.
+func parseWireGuardPrefix(field, value string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix, nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid %s: %s", field, value)
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// wireGuardEndpoint returns host:port, adding the port when endpoint is a
+// bare host.
+func wireGuardEndpoint(endpoint, port string) (string, error) {
+	if endpoint == "" {
+		return "", fmt.Errorf("endpoint is required")
+	}
+
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return endpoint, nil
+	}
+
+	if port == "" {
+		return "", fmt.Errorf("invalid endpoint: %s, the interface has no listen_port", endpoint)
+	}
+
+	return net.JoinHostPort(strings.Trim(endpoint, "[]"), port), nil
+}
+
+func wireGuardPeerType(iface string) string {
+	return "wireguard_" + iface
+}
+
+func findWireGuardPeer(peers map[string]WireGuardPeer, publicKey string) (string, bool) {
+	for cfg, peer := range peers {
+		if peer.PublicKey == publicKey {
+			return cfg, true
+		}
+	}
+
+	return "", false
+}
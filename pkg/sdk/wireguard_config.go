@@ -0,0 +1,351 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/renanqts/openwrt-sdk/internal/qrcode"
+)
+
+// wgQuickOptions are the wg-quick options with no UCI counterpart, skipped
+// when parsing
+var wgQuickOptions = []string{"table", "fwmark", "saveconfig", "preup", "postup", "predown", "postdown"}
+
+// ImportWireGuardConfig creates a WireGuard interface and its peers from a
+// wg-quick configuration, then commits and reloads the network. The policy
+// of the import, if any, points at the interface and is committed after the
+// network. Everything, the policy included, is validated before the first
+// write, so nothing is written when any of it is invalid or already used.
+func (o *OpenWRT) ImportWireGuardConfig(ctx context.Context, wgImport WireGuardImport) error {
+	if err := wgImport.Config.Validate(); err != nil {
+		return err
+	}
+
+	iface := WireGuardInterface{
+		Name:       wgImport.Interface,
+		Proto:      "wireguard",
+		PrivateKey: wgImport.Config.PrivateKey,
+		ListenPort: wgImport.Config.ListenPort,
+		Addresses:  wgImport.Config.Addresses,
+		MTU:        wgImport.Config.MTU,
+		DNS:        wgImport.Config.DNS,
+	}
+	if err := iface.Validate(); err != nil {
+		return err
+	}
+
+	var peers []WireGuardPeer
+	sections := make(map[string]WireGuardPeer, len(wgImport.Config.Peers))
+	for index, configPeer := range wgImport.Config.Peers {
+		peer := WireGuardPeer{
+			Type:                wireGuardPeerType(iface.Name),
+			PublicKey:           configPeer.PublicKey,
+			PresharedKey:        configPeer.PresharedKey,
+			AllowedIPs:          configPeer.AllowedIPs,
+			PersistentKeepalive: configPeer.PersistentKeepalive,
+		}
+		if configPeer.Endpoint != "" {
+			peer.EndpointHost, peer.EndpointPort, _ = net.SplitHostPort(configPeer.Endpoint)
+		}
+		if wgImport.RouteAllowedIPs {
+			peer.RouteAllowedIPs = "1"
+		}
+
+		if err := peer.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := findWireGuardPeer(sections, peer.PublicKey); ok {
+			return fmt.Errorf("%w: %s is listed twice", ErrConflict, peer.PublicKey)
+		}
+		sections[fmt.Sprintf("new peer %d", index)] = peer
+		peers = append(peers, peer)
+	}
+
+	if err := checkWireGuardPeers(sections); err != nil {
+		return err
+	}
+
+	kinds, err := o.sectionKinds(ctx, "network")
+	if err != nil {
+		return err
+	}
+
+	if err := checkSectionName(kinds, "network", iface.Name); err != nil {
+		return err
+	}
+
+	policy := wgImport.Policy
+	if policy.Name != "" {
+		interfaces, err := o.GetInterfaces(ctx)
+		if err != nil {
+			return err
+		}
+		interfaces[iface.Name] = Interface{Type: "interface", Name: iface.Name, Proto: iface.Proto}
+
+		policy.Interface = iface.Name
+		if err := checkPBRPolicy(policy, interfaces); err != nil {
+			return err
+		}
+
+		policies, err := o.GetPBRPolicies(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := findPBRPolicy(policies, policy.Name); ok {
+			return fmt.Errorf("%w: pbr policy %s already exists", ErrConflict, policy.Name)
+		}
+	}
+
+	if err := o.addNamedSection(ctx, "network", "interface", iface.Name, iface); err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if _, err := o.addSection(ctx, "network", peer.Type, peer); err != nil {
+			return err
+		}
+	}
+
+	if policy.Name != "" {
+		if _, err := o.addSection(ctx, "pbr", "policy", policy); err != nil {
+			return err
+		}
+	}
+
+	if err := o.commitNetwork(ctx); err != nil {
+		return err
+	}
+
+	if policy.Name == "" {
+		return nil
+	}
+
+	if _, err := o.lucirpc.Uci(ctx, "commit", []string{"pbr"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ParseWireGuardConfig parses a wg-quick configuration file. The wg-quick
+// options without a UCI counterpart, such as PostUp or Table, are skipped.
+func ParseWireGuardConfig(data string) (WireGuardConfig, error) {
+	var (
+		config       WireGuardConfig
+		section      string
+		hasInterface bool
+	)
+	for number, line := range strings.Split(data, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			switch section {
+			case "interface":
+				if hasInterface {
+					return WireGuardConfig{}, fmt.Errorf("line %d: [Interface] is repeated", number+1)
+				}
+				hasInterface = true
+			case "peer":
+				config.Peers = append(config.Peers, WireGuardConfigPeer{})
+			default:
+				return WireGuardConfig{}, fmt.Errorf("line %d: unknown section %s", number+1, line)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return WireGuardConfig{}, fmt.Errorf("line %d: expected key = value", number+1)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var known bool
+		switch section {
+		case "interface":
+			known = parseWireGuardInterfaceOption(&config, key, value)
+		case "peer":
+			known = parseWireGuardPeerOption(&config.Peers[len(config.Peers)-1], key, value)
+		default:
+			return WireGuardConfig{}, fmt.Errorf("line %d: %s is outside of a section", number+1, key)
+		}
+
+		if !known {
+			return WireGuardConfig{}, fmt.Errorf("line %d: unknown option %s", number+1, key)
+		}
+	}
+
+	if !hasInterface {
+		return WireGuardConfig{}, fmt.Errorf("[Interface] is missing")
+	}
+
+	if err := config.Validate(); err != nil {
+		return WireGuardConfig{}, err
+	}
+
+	return config, nil
+}
+
+// Validate checks the keys, addresses and peers of a wg-quick configuration.
+func (c WireGuardConfig) Validate() error {
+	if err := validateWireGuardKey("private_key", c.PrivateKey); err != nil {
+		return err
+	}
+
+	for _, address := range c.Addresses {
+		if _, err := parseWireGuardPrefix("address", address); err != nil {
+			return err
+		}
+	}
+
+	for _, dns := range c.DNS {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			return fmt.Errorf("invalid dns: %s", dns)
+		}
+	}
+
+	if err := validateUint("listen_port", c.ListenPort, 1, 65535); err != nil {
+		return err
+	}
+
+	if err := validateUint("mtu", c.MTU, 1280, 65535); err != nil {
+		return err
+	}
+
+	for _, peer := range c.Peers {
+		if err := peer.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the keys, allowed IPs and endpoint of a wg-quick peer.
+func (p WireGuardConfigPeer) Validate() error {
+	if err := validateWireGuardKey("public_key", p.PublicKey); err != nil {
+		return err
+	}
+
+	if p.PresharedKey != "" {
+		if err := validateWireGuardKey("preshared_key", p.PresharedKey); err != nil {
+			return err
+		}
+	}
+
+	for _, allowedIP := range p.AllowedIPs {
+		if _, err := parseWireGuardPrefix("allowed_ips", allowedIP); err != nil {
+			return err
+		}
+	}
+
+	if p.Endpoint != "" {
+		if _, port, err := net.SplitHostPort(p.Endpoint); err != nil || validateUint("endpoint", port, 1, 65535) != nil {
+			return fmt.Errorf("invalid endpoint: %s, expected host:port", p.Endpoint)
+		}
+	}
+
+	return validateUint("persistent_keepalive", p.PersistentKeepalive, 0, 65535)
+}
+
+// String renders the configuration in the wg-quick format.
+func (c WireGuardConfig) String() string {
+	var b strings.Builder
+	b.WriteString("[Interface]\n")
+	writeWireGuardOption(&b, "PrivateKey", c.PrivateKey)
+	writeWireGuardOption(&b, "Address", strings.Join(c.Addresses, ", "))
+	writeWireGuardOption(&b, "DNS", strings.Join(c.DNS, ", "))
+	writeWireGuardOption(&b, "ListenPort", c.ListenPort)
+	writeWireGuardOption(&b, "MTU", c.MTU)
+
+	for _, peer := range c.Peers {
+		b.WriteString("\n[Peer]\n")
+		writeWireGuardOption(&b, "PublicKey", peer.PublicKey)
+		writeWireGuardOption(&b, "PresharedKey", peer.PresharedKey)
+		writeWireGuardOption(&b, "AllowedIPs", strings.Join(peer.AllowedIPs, ", "))
+		writeWireGuardOption(&b, "Endpoint", peer.Endpoint)
+		writeWireGuardOption(&b, "PersistentKeepalive", peer.PersistentKeepalive)
+	}
+
+	return b.String()
+}
+
+// QRCode renders the configuration as a QR code printable on a terminal,
+// ready to be scanned by the WireGuard mobile apps.
+func (c WireGuardConfig) QRCode() (string, error) {
+	code, err := qrcode.Encode([]byte(c.String()), qrcode.Low)
+	if err != nil {
+		return "", err
+	}
+
+	return code.Terminal(), nil
+}
+
+func parseWireGuardInterfaceOption(config *WireGuardConfig, key, value string) bool {
+	switch key {
+	case "privatekey":
+		config.PrivateKey = value
+	case "address":
+		config.Addresses = append(config.Addresses, wireGuardValues(value)...)
+	case "dns":
+		config.DNS = append(config.DNS, wireGuardValues(value)...)
+	case "listenport":
+		config.ListenPort = value
+	case "mtu":
+		config.MTU = value
+	default:
+		return slices.Contains(wgQuickOptions, key)
+	}
+
+	return true
+}
+
+func parseWireGuardPeerOption(peer *WireGuardConfigPeer, key, value string) bool {
+	switch key {
+	case "publickey":
+		peer.PublicKey = value
+	case "presharedkey":
+		peer.PresharedKey = value
+	case "allowedips":
+		peer.AllowedIPs = append(peer.AllowedIPs, wireGuardValues(value)...)
+	case "endpoint":
+		peer.Endpoint = value
+	case "persistentkeepalive":
+		if value != "off" {
+			peer.PersistentKeepalive = value
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// wireGuardValues splits a comma separated wg-quick value.
+func wireGuardValues(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
+func writeWireGuardOption(b *strings.Builder, key, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "%s = %s\n", key, value)
+	}
+}
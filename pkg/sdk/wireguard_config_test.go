@@ -0,0 +1,161 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("WireGuard configurations", func() {
+	var (
+		ctx       context.Context
+		router    *fakerouter.Router
+		serverKey string
+		psk       string
+		data      string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "wan", map[string]any{"proto": "dhcp"})
+		router.AddSection("pbr", "pbr", "config", map[string]any{"enabled": "1"})
+
+		var err error
+		_, serverKey, err = GenerateWireGuardKeyPair()
+		Expect(err).To(BeNil())
+		psk, err = GenerateWireGuardPresharedKey()
+		Expect(err).To(BeNil())
+
+		data = `# provider config
+[Interface]
+PrivateKey = ` + testWireGuardPrivateKey + `
+Address = 10.64.0.2/32, fd00:64::2/128
+DNS = 10.64.0.1
+PostUp = iptables -A FORWARD -i %i -j ACCEPT
+
+[peer]
+PublicKey = ` + serverKey + `
+PresharedKey = ` + psk + `
+AllowedIPs = 0.0.0.0/0
+AllowedIPs = ::/0
+Endpoint = [2001:db8::1]:51820 # primary
+PersistentKeepalive = off
+`
+	})
+
+	It("parse and render a wg-quick configuration", func() {
+		config, err := ParseWireGuardConfig(data)
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(WireGuardConfig{
+			PrivateKey: testWireGuardPrivateKey,
+			Addresses:  []string{"10.64.0.2/32", "fd00:64::2/128"},
+			DNS:        []string{"10.64.0.1"},
+			Peers: []WireGuardConfigPeer{{
+				PublicKey:    serverKey,
+				PresharedKey: psk,
+				AllowedIPs:   []string{"0.0.0.0/0", "::/0"},
+				Endpoint:     "[2001:db8::1]:51820",
+			}},
+		}))
+
+		parsed, err := ParseWireGuardConfig(config.String())
+		Expect(err).To(BeNil())
+		Expect(parsed).To(Equal(config))
+	})
+
+	It("reject invalid wg-quick configurations", func() {
+		invalid := map[string]string{
+			"PrivateKey = " + testWireGuardPrivateKey: "line 1: privatekey is outside of a section",
+			"[Peer]\nPublicKey = " + serverKey:        "[Interface] is missing",
+			"[Interface]\n[Interface]":                "line 2: [Interface] is repeated",
+			"[Server]":                                "line 1: unknown section [Server]",
+			"[Interface]\nPrivateKey":                 "line 2: expected key = value",
+			"[Interface]\nFoo = bar":                  "line 2: unknown option foo",
+			"[Interface]\nPrivateKey = " + testWireGuardPrivateKey + "\nMTU = 80":                                                          "invalid mtu: 80",
+			"[Interface]\nPrivateKey = " + testWireGuardPrivateKey + "\n[Peer]\nPublicKey = " + serverKey + "\nEndpoint = vpn.example.com": "invalid endpoint: vpn.example.com, expected host:port",
+		}
+		for data, message := range invalid {
+			_, err := ParseWireGuardConfig(data)
+			Expect(err).To(MatchError(ContainSubstring(message)), data)
+		}
+	})
+
+	It("import a configuration with a policy", func() {
+		o := newFakeOpenWRT(router)
+		config, err := ParseWireGuardConfig(data)
+		Expect(err).To(BeNil())
+
+		wgImport := WireGuardImport{
+			Interface: "provider",
+			Config:    config,
+			Policy:    PBR{Name: "tv", SrcAddr: "192.168.1.50"},
+		}
+		Expect(o.ImportWireGuardConfig(ctx, wgImport)).To(Succeed())
+
+		ifaces, err := o.GetWireGuardInterfaces(ctx)
+		Expect(err).To(BeNil())
+		Expect(ifaces).To(Equal(map[string]WireGuardInterface{"provider": {
+			Type:       "interface",
+			Name:       "provider",
+			Proto:      "wireguard",
+			PrivateKey: testWireGuardPrivateKey,
+			Addresses:  UciList{"10.64.0.2/32", "fd00:64::2/128"},
+			DNS:        UciList{"10.64.0.1"},
+		}}))
+		Expect(o.GetWireGuardPeers(ctx, "provider")).To(ConsistOf(WireGuardPeer{
+			Type:         "wireguard_provider",
+			PublicKey:    serverKey,
+			PresharedKey: psk,
+			AllowedIPs:   UciList{"0.0.0.0/0", "::/0"},
+			EndpointHost: "2001:db8::1",
+			EndpointPort: "51820",
+		}))
+		Expect(router.Sections("pbr", "policy")).To(ConsistOf(And(
+			HaveKeyWithValue("name", "tv"),
+			HaveKeyWithValue("interface", "provider"),
+		)))
+		Expect(router.Commits("network")).To(Equal(1))
+		Expect(router.Commits("pbr")).To(Equal(1))
+
+		err = o.ImportWireGuardConfig(ctx, wgImport)
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		router.AddSection("network", "globals", "globals", map[string]any{"ula_prefix": "fd00::/48"})
+		err = o.ImportWireGuardConfig(ctx, WireGuardImport{Interface: "globals", Config: config})
+		Expect(err).To(MatchError(ContainSubstring("network.globals already exists as a globals section")))
+		Expect(router.Sections("network", "globals")).To(HaveLen(1))
+
+		wgImport.Interface = "provider2"
+		wgImport.RouteAllowedIPs = true
+		err = o.ImportWireGuardConfig(ctx, wgImport)
+		Expect(err).To(MatchError(ContainSubstring("pbr policy tv already exists")))
+
+		wgImport.Policy = PBR{Name: "phone", SrcAddr: "192.168.1.51", SrcPort: "http"}
+		Expect(o.ImportWireGuardConfig(ctx, wgImport)).ToNot(Succeed())
+		Expect(router.Sections("network", "interface")).To(HaveLen(2))
+		Expect(router.Sections("network", "wireguard_provider2")).To(BeEmpty())
+		Expect(router.Sections("pbr", "policy")).To(HaveLen(1))
+		Expect(router.Commits("network")).To(Equal(1))
+
+		wgImport.Policy = PBR{}
+		Expect(o.ImportWireGuardConfig(ctx, wgImport)).To(Succeed())
+		Expect(router.Sections("network", "wireguard_provider2")).To(ConsistOf(HaveKeyWithValue("route_allowed_ips", "1")))
+		Expect(router.Commits("pbr")).To(Equal(1))
+	})
+
+	It("render a QR code", func() {
+		config, err := ParseWireGuardConfig(data)
+		Expect(err).To(BeNil())
+
+		qr, err := config.QRCode()
+		Expect(err).To(BeNil())
+		lines := strings.Split(strings.TrimSuffix(qr, "\n"), "\n")
+		Expect(len(lines)).To(BeNumerically(">", 20))
+		Expect(lines[0]).To(Equal(strings.Repeat("█", len([]rune(lines[1])))))
+	})
+})
@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateWireGuardKeyPair generates a Curve25519 key pair, like wg genkey and
+// wg pubkey, and returns both keys in base64.
+func GenerateWireGuardKeyPair() (privateKey, publicKey string, err error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", "", err
+	}
+
+	// clamp the scalar as WireGuard does
+	key[0] &= 248
+	key[31] = key[31]&127 | 64
+
+	privateKey = base64.StdEncoding.EncodeToString(key)
+	publicKey, err = WireGuardPublicKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// GenerateWireGuardPresharedKey generates a random preshared key, like wg
+// genpsk, in base64.
+func GenerateWireGuardPresharedKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// WireGuardPublicKey returns the public key of a base64 private key.
+func WireGuardPublicKey(privateKey string) (string, error) {
+	key, err := decodeWireGuardKey("private_key", privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	private, err := ecdh.X25519().NewPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(private.PublicKey().Bytes()), nil
+}
+
+// validateWireGuardKey checks a base64 WireGuard key.
+func validateWireGuardKey(field, key string) error {
+	_, err := decodeWireGuardKey(field, key)
+	return err
+}
+
+func decodeWireGuardKey(field, key string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(decoded) != 32 {
+		return nil, fmt.Errorf("invalid %s, expected a base64 encoded 32 bytes key", field)
+	}
+
+	return decoded, nil
+}
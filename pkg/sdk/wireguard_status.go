@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	wireGuardDumpCommand = "wg show all dump"
+	// wireGuardHandshakeTimeout is how long a session lasts without a new
+	// handshake, REJECT_AFTER_TIME in the WireGuard protocol
+	wireGuardHandshakeTimeout = 3 * time.Minute
+)
+
+// GetWireGuardStatuses retrieves the runtime state of the WireGuard
+// interfaces and their peers, keyed by interface, with the descriptions of
+// the peers configured in UCI.
+func (o *OpenWRT) GetWireGuardStatuses(ctx context.Context) (map[string]WireGuardStatus, error) {
+	output, err := o.exec(ctx, wireGuardDumpCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := parseWireGuardDump(output)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]string, 0, len(statuses))
+	for name := range statuses {
+		kinds = append(kinds, wireGuardPeerType(name))
+	}
+
+	peers, err := getSections[WireGuardPeer](ctx, o, "network", kinds...)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, status := range statuses {
+		for index, peerStatus := range status.Peers {
+			for _, peer := range peers {
+				if peer.Type == wireGuardPeerType(name) && peer.PublicKey == peerStatus.PublicKey {
+					status.Peers[index].Description = peer.Description
+				}
+			}
+		}
+	}
+
+	return statuses, nil
+}
+
+// GetWireGuardStatus retrieves the runtime state of a WireGuard interface and
+// its peers.
+func (o *OpenWRT) GetWireGuardStatus(ctx context.Context, iface string) (WireGuardStatus, error) {
+	statuses, err := o.GetWireGuardStatuses(ctx)
+	if err != nil {
+		return WireGuardStatus{}, err
+	}
+
+	status, ok := statuses[iface]
+	if !ok {
+		return WireGuardStatus{}, fmt.Errorf("%w: wireguard interface %s is not running", ErrSectionNotFound, iface)
+	}
+
+	return status, nil
+}
+
+// Connected reports whether the peer completed a handshake recently enough
+// for its session to be alive at now.
+func (p WireGuardPeerStatus) Connected(now time.Time) bool {
+	return !p.LatestHandshake.IsZero() && now.Sub(p.LatestHandshake) < wireGuardHandshakeTimeout
+}
+
+// parseWireGuardDump parses the output of wg show all dump: a line per
+// interface followed by a line per peer, with tab separated fields.
+func parseWireGuardDump(output string) (map[string]WireGuardStatus, error) {
+	statuses := make(map[string]WireGuardStatus)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		switch len(fields) {
+		case 5:
+			// interface, private key, public key, listen port, fwmark
+			port, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("unexpected wg show dump line: %q", line)
+			}
+
+			statuses[fields[0]] = WireGuardStatus{
+				Interface:  fields[0],
+				PublicKey:  fields[2],
+				ListenPort: port,
+			}
+		case 9:
+			// interface, public key, preshared key, endpoint, allowed ips,
+			// latest handshake, received, sent, persistent keepalive
+			status, ok := statuses[fields[0]]
+			if !ok {
+				return nil, fmt.Errorf("unexpected wg show dump line: %q", line)
+			}
+
+			peer, err := parseWireGuardDumpPeer(fields)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected wg show dump line: %q", line)
+			}
+			status.Peers = append(status.Peers, peer)
+			statuses[fields[0]] = status
+		default:
+			return nil, fmt.Errorf("unexpected wg show dump line: %q", line)
+		}
+	}
+
+	return statuses, nil
+}
+
+func parseWireGuardDumpPeer(fields []string) (WireGuardPeerStatus, error) {
+	peer := WireGuardPeerStatus{PublicKey: fields[1]}
+	if fields[3] != "(none)" {
+		peer.Endpoint = fields[3]
+	}
+
+	if fields[4] != "(none)" {
+		peer.AllowedIPs = strings.Split(fields[4], ",")
+	}
+
+	handshake, err := strconv.ParseInt(fields[5], 10, 64)
+	if err != nil {
+		return WireGuardPeerStatus{}, err
+	}
+	if handshake > 0 {
+		peer.LatestHandshake = time.Unix(handshake, 0)
+	}
+
+	if peer.ReceivedBytes, err = strconv.ParseUint(fields[6], 10, 64); err != nil {
+		return WireGuardPeerStatus{}, err
+	}
+
+	if peer.SentBytes, err = strconv.ParseUint(fields[7], 10, 64); err != nil {
+		return WireGuardPeerStatus{}, err
+	}
+
+	if fields[8] != "off" {
+		keepalive, err := strconv.Atoi(fields[8])
+		if err != nil {
+			return WireGuardPeerStatus{}, err
+		}
+		peer.PersistentKeepalive = time.Duration(keepalive) * time.Second
+	}
+
+	return peer, nil
+}
@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+var _ = Describe("WireGuard status", func() {
+	var (
+		ctx    context.Context
+		router *fakerouter.Router
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "vpn", map[string]any{"proto": "wireguard", "private_key": testWireGuardPrivateKey})
+		router.AddSection("network", "wireguard_vpn", "", map[string]any{"description": "phone", "public_key": "phoneKey="})
+		router.SetOutput(wireGuardDumpCommand, strings.Join([]string{
+			"vpn\t" + testWireGuardPrivateKey + "\t" + testWireGuardPublicKey + "\t51820\toff",
+			"vpn\tphoneKey=\t(none)\t198.51.100.7:40112\t10.8.0.2/32,fd00:8::2/128\t1700000000\t1024\t4096\t25",
+			"vpn\tlaptopKey=\tpskKey=\t(none)\t(none)\t0\t0\t0\toff",
+			"wg1\t(none)\twg1Key=\t0\t0x10",
+			"",
+		}, "\n"))
+	})
+
+	It("get the status of the interfaces and their peers", func() {
+		o := newFakeOpenWRT(router)
+		statuses, err := o.GetWireGuardStatuses(ctx)
+		Expect(err).To(BeNil())
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses["wg1"]).To(Equal(WireGuardStatus{Interface: "wg1", PublicKey: "wg1Key="}))
+
+		status, err := o.GetWireGuardStatus(ctx, "vpn")
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(WireGuardStatus{
+			Interface:  "vpn",
+			PublicKey:  testWireGuardPublicKey,
+			ListenPort: 51820,
+			Peers: []WireGuardPeerStatus{
+				{
+					PublicKey:           "phoneKey=",
+					Description:         "phone",
+					Endpoint:            "198.51.100.7:40112",
+					AllowedIPs:          []string{"10.8.0.2/32", "fd00:8::2/128"},
+					LatestHandshake:     time.Unix(1700000000, 0),
+					ReceivedBytes:       1024,
+					SentBytes:           4096,
+					PersistentKeepalive: 25 * time.Second,
+				},
+				{PublicKey: "laptopKey="},
+			},
+		}))
+
+		handshake := time.Unix(1700000000, 0)
+		Expect(status.Peers[0].Connected(handshake.Add(time.Minute))).To(BeTrue())
+		Expect(status.Peers[0].Connected(handshake.Add(5 * time.Minute))).To(BeFalse())
+		Expect(status.Peers[1].Connected(handshake)).To(BeFalse())
+
+		_, err = o.GetWireGuardStatus(ctx, "lan")
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		Expect(router.History()).To(HaveLen(3))
+	})
+
+	It("handle routers without WireGuard", func() {
+		o := newFakeOpenWRT(router)
+		router.SetOutput(wireGuardDumpCommand, "")
+		Expect(o.GetWireGuardStatuses(ctx)).To(BeEmpty())
+
+		router.SetOutput(wireGuardDumpCommand, "sh: wg: not found")
+		_, err := o.GetWireGuardStatuses(ctx)
+		Expect(err).To(MatchError(`unexpected wg show dump line: "sh: wg: not found"`))
+	})
+})
@@ -0,0 +1,211 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/renanqts/openwrt-sdk/internal/fakerouter"
+)
+
+const (
+	testWireGuardPrivateKey = "dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo="
+	testWireGuardPublicKey  = "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo="
+)
+
+var _ = Describe("WireGuard", func() {
+	var (
+		ctx      context.Context
+		router   *fakerouter.Router
+		phoneKey string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		router = fakerouter.New("root", "password")
+		router.AddSection("network", "interface", "lan", map[string]any{"proto": "static", "ipaddr": "192.168.1.1/24"})
+		router.AddSection("network", "interface", "vpn", map[string]any{
+			"proto":       "wireguard",
+			"private_key": testWireGuardPrivateKey,
+			"listen_port": "51820",
+			"addresses":   []string{"10.8.0.1/24"},
+		})
+
+		var err error
+		_, phoneKey, err = GenerateWireGuardKeyPair()
+		Expect(err).To(BeNil())
+		router.AddSection("network", "wireguard_vpn", "", map[string]any{
+			"description": "phone",
+			"public_key":  phoneKey,
+			"allowed_ips": []string{"10.8.0.2/32"},
+		})
+	})
+
+	It("generate keys", func() {
+		Expect(WireGuardPublicKey(testWireGuardPrivateKey)).To(Equal(testWireGuardPublicKey))
+		_, err := WireGuardPublicKey("c2hvcnQ=")
+		Expect(err).To(MatchError("invalid private_key, expected a base64 encoded 32 bytes key"))
+
+		privateKey, publicKey, err := GenerateWireGuardKeyPair()
+		Expect(err).To(BeNil())
+		Expect(WireGuardPublicKey(privateKey)).To(Equal(publicKey))
+		Expect(privateKey).ToNot(Equal(publicKey))
+
+		psk, err := GenerateWireGuardPresharedKey()
+		Expect(err).To(BeNil())
+		Expect(validateWireGuardKey("preshared_key", psk)).To(Succeed())
+	})
+
+	It("create, update and delete an interface with its peers", func() {
+		o := newFakeOpenWRT(router)
+		Expect(o.GetWireGuardInterfaces(ctx)).To(HaveLen(1))
+
+		iface := WireGuardInterface{Name: "site", ListenPort: "51821", Addresses: UciList{"10.9.0.1/24"}}
+		Expect(o.SetWireGuardInterfaces(ctx, []WireGuardInterface{iface})).To(Succeed())
+		ifaces, err := o.GetWireGuardInterfaces(ctx)
+		Expect(err).To(BeNil())
+		Expect(ifaces["site"].Proto).To(Equal("wireguard"))
+		Expect(validateWireGuardKey("private_key", ifaces["site"].PrivateKey)).To(Succeed())
+
+		err = o.SetWireGuardInterfaces(ctx, []WireGuardInterface{{Name: "lan"}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		router.AddSection("network", "device", "wg_dev", map[string]any{"name": "br-wg", "type": "bridge"})
+		err = o.SetWireGuardInterfaces(ctx, []WireGuardInterface{{Name: "wg_dev"}})
+		Expect(err).To(MatchError(ContainSubstring("network.wg_dev already exists as a device section")))
+		Expect(router.Sections("network", "device")).To(ConsistOf(HaveKeyWithValue(".name", "wg_dev")))
+
+		iface = ifaces["site"]
+		iface.MTU = "1420"
+		Expect(o.UpdateWireGuardInterfaces(ctx, []WireGuardInterface{iface})).To(Succeed())
+		Expect(router.Sections("network", "interface")).To(ContainElement(And(
+			HaveKeyWithValue(".name", "site"),
+			HaveKeyWithValue("mtu", "1420"),
+		)))
+		Expect(o.UpdateWireGuardInterfaces(ctx, []WireGuardInterface{{Name: "lan", PrivateKey: testWireGuardPrivateKey}})).
+			To(MatchError("wireguard interfaces not found: [lan]"))
+
+		Expect(o.DeleteWireGuardInterfaces(ctx, []string{"vpn"})).To(Succeed())
+		Expect(router.Sections("network", "wireguard_vpn")).To(BeEmpty())
+		Expect(o.GetWireGuardInterfaces(ctx)).To(HaveLen(1))
+		Expect(router.Commits("network")).To(Equal(3))
+		Expect(router.History()).To(HaveLen(3))
+	})
+
+	It("create, update and delete peers", func() {
+		o := newFakeOpenWRT(router)
+		_, laptopKey, err := GenerateWireGuardKeyPair()
+		Expect(err).To(BeNil())
+		laptop := WireGuardPeer{
+			Description:         "laptop",
+			PublicKey:           laptopKey,
+			AllowedIPs:          UciList{"10.8.0.3/32", "192.168.50.0/24"},
+			EndpointHost:        "laptop.example.com",
+			EndpointPort:        "51820",
+			PersistentKeepalive: "25",
+			RouteAllowedIPs:     "1",
+		}
+		Expect(o.SetWireGuardPeers(ctx, "vpn", []WireGuardPeer{laptop})).To(Succeed())
+		peers, err := o.GetWireGuardPeers(ctx, "vpn")
+		Expect(err).To(BeNil())
+		laptop.Type = "wireguard_vpn"
+		Expect(peers).To(ContainElement(laptop))
+
+		err = o.SetWireGuardPeers(ctx, "vpn", []WireGuardPeer{{PublicKey: phoneKey}})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		err = o.SetWireGuardPeers(ctx, "lan", []WireGuardPeer{laptop})
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+
+		laptop.AllowedIPs = UciList{"10.8.0.2"}
+		err = o.UpdateWireGuardPeers(ctx, "vpn", []WireGuardPeer{laptop})
+		Expect(err).To(MatchError(ContainSubstring("allowed ip 10.8.0.2/32 belongs to peers")))
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+
+		laptop.AllowedIPs = UciList{"10.8.0.3/32"}
+		laptop.EndpointHost = ""
+		laptop.EndpointPort = ""
+		Expect(o.UpdateWireGuardPeers(ctx, "vpn", []WireGuardPeer{laptop})).To(Succeed())
+		Expect(router.Sections("network", "wireguard_vpn")).To(ContainElement(And(
+			HaveKeyWithValue("public_key", laptopKey),
+			Not(HaveKey("endpoint_host")),
+		)))
+
+		Expect(o.DeleteWireGuardPeers(ctx, "vpn", []string{laptopKey, phoneKey})).To(Succeed())
+		Expect(router.Sections("network", "wireguard_vpn")).To(BeEmpty())
+		Expect(o.DeleteWireGuardPeers(ctx, "vpn", []string{laptopKey})).To(MatchError("wireguard peers not found: [" + laptopKey + "]"))
+	})
+
+	It("add a client and render its configuration", func() {
+		o := newFakeOpenWRT(router)
+		config, err := o.AddWireGuardClient(ctx, "vpn", WireGuardClient{
+			Description:         "tablet",
+			Addresses:           []string{"10.8.0.4/24"},
+			DNS:                 []string{"10.8.0.1"},
+			Endpoint:            "vpn.example.com",
+			PersistentKeepalive: "25",
+			PresharedKey:        true,
+		})
+		Expect(err).To(BeNil())
+
+		peers := router.Sections("network", "wireguard_vpn")
+		Expect(peers).To(HaveLen(2))
+		tablet := peers[1]
+		Expect(tablet).To(HaveKeyWithValue("description", "tablet"))
+		Expect(tablet).To(HaveKeyWithValue("allowed_ips", ConsistOf("10.8.0.4/32")))
+		Expect(tablet).To(HaveKeyWithValue("route_allowed_ips", "1"))
+
+		lines := strings.Split(config, "\n")
+		Expect(lines[0]).To(Equal("[Interface]"))
+		Expect(lines[1]).To(HavePrefix("PrivateKey = "))
+		Expect(WireGuardPublicKey(strings.TrimPrefix(lines[1], "PrivateKey = "))).To(Equal(tablet["public_key"]))
+		Expect(lines[2:]).To(Equal([]string{
+			"Address = 10.8.0.4/24",
+			"DNS = 10.8.0.1",
+			"",
+			"[Peer]",
+			"PublicKey = " + testWireGuardPublicKey,
+			"PresharedKey = " + tablet["preshared_key"].(string),
+			"AllowedIPs = 0.0.0.0/0, ::/0",
+			"Endpoint = vpn.example.com:51820",
+			"PersistentKeepalive = 25",
+			"",
+		}))
+
+		_, err = o.AddWireGuardClient(ctx, "vpn", WireGuardClient{Addresses: []string{"10.8.0.2/32"}, Endpoint: "[2001:db8::1]:443"})
+		Expect(errors.Is(err, ErrConflict)).To(BeTrue())
+		_, err = o.AddWireGuardClient(ctx, "vpn", WireGuardClient{Addresses: []string{"10.8.0.5/32"}})
+		Expect(err).To(MatchError("endpoint is required"))
+		_, err = o.AddWireGuardClient(ctx, "lan", WireGuardClient{Addresses: []string{"10.8.0.5/32"}, Endpoint: "vpn.example.com"})
+		Expect(errors.Is(err, ErrSectionNotFound)).To(BeTrue())
+		Expect(router.Sections("network", "wireguard_vpn")).To(HaveLen(2))
+	})
+
+	It("validate", func() {
+		invalidIfaces := []WireGuardInterface{
+			{PrivateKey: testWireGuardPrivateKey},
+			{Name: "vpn"},
+			{Name: "vpn", PrivateKey: testWireGuardPrivateKey, ListenPort: "70000"},
+			{Name: "vpn", PrivateKey: testWireGuardPrivateKey, Addresses: UciList{"10.8.0.1/33"}},
+			{Name: "vpn", PrivateKey: testWireGuardPrivateKey, MTU: "1000"},
+		}
+		for _, iface := range invalidIfaces {
+			Expect(iface.Validate()).ToNot(Succeed(), "%+v", iface)
+		}
+
+		invalidPeers := []WireGuardPeer{
+			{},
+			{PublicKey: testWireGuardPublicKey, PresharedKey: "psk"},
+			{PublicKey: testWireGuardPublicKey, AllowedIPs: UciList{"example.com"}},
+			{PublicKey: testWireGuardPublicKey, EndpointPort: "51820"},
+			{PublicKey: testWireGuardPublicKey, EndpointHost: "vpn example"},
+			{PublicKey: testWireGuardPublicKey, EndpointHost: "vpn.example.com", EndpointPort: "0"},
+			{PublicKey: testWireGuardPublicKey, PersistentKeepalive: "-1"},
+			{PublicKey: testWireGuardPublicKey, RouteAllowedIPs: "true"},
+		}
+		for _, peer := range invalidPeers {
+			Expect(peer.Validate()).ToNot(Succeed(), "%+v", peer)
+		}
+	})
+})